@@ -0,0 +1,322 @@
+// Command snek_admin talks to the admin service of a running snek server.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	addr   = flag.String("addr", "localhost:6000", "the address of the snek server")
+	token  = flag.String("token", os.Getenv("SNEK_ADMIN_TOKEN"), "the server's -admin_token, defaults to $SNEK_ADMIN_TOKEN")
+	useTLS = flag.Bool("tls", false, "whether or not to connect to the server over TLS")
+	caFile = flag.String("ca", "", "a CA certificate to verify the server with instead of the system's, implies -tls")
+)
+
+const usage = `usage: snek_admin [flags] <command> [args]
+
+commands:
+  rooms                                   list rooms and the sneks in them
+  kick <id> [reason]                      kick a snek
+  ban [-name name] [-host host] [reason]  ban a player by name or host, kicking them if they're on
+  end <room> [reason]                     end the game in a room
+  config <room> [-tick d] [-wrap=bool] [-margin n] [-mode ffa|royale|teams]
+         [-shrink n] [-teams n] [-friendly_fire=bool] [-scoring length|kills]
+                                          change a room's rules
+  announce [-room room] <message>         send a notice to a room, or everyone
+
+  tournament create [-format roundrobin|elimination] [-start d] [-ticks n] <name> <entrant>...
+                                          create a tournament, where entrants are player names,
+                                          or name=url for Battlesnake bots
+  tournament add <name> <entrant>         add an entrant to a tournament before it starts
+  tournament show <name>                  show a tournament's standings and matches
+
+flags:
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	creds, err := dialCreds()
+	if err != nil {
+		log.Fatalf("failed to load TLS credentials: %v", err)
+	}
+	conn, err := grpc.Dial(*addr, creds)
+	if err != nil {
+		log.Fatalf("failed to dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs("authorization", "Bearer "+*token))
+
+	if flag.Arg(0) == "tournament" {
+		err = runTournament(ctx, pb.NewAdminClient(conn), pb.NewSnekClient(conn), flag.Args()[1:])
+	} else {
+		err = run(ctx, pb.NewAdminClient(conn), flag.Arg(0), flag.Args()[1:])
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, c pb.AdminClient, cmd string, args []string) error {
+	switch cmd {
+	case "rooms":
+		return listRooms(ctx, c)
+	case "kick":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: kick <id> [reason]")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid snek id %q: %v", args[0], err)
+		}
+		return report(c.Kick(ctx, &pb.KickRequest{Id: int32(id), Reason: strings.Join(args[1:], " ")}))
+	case "ban":
+		fs := flag.NewFlagSet("ban", flag.ExitOnError)
+		name := fs.String("name", "", "the name to ban")
+		host := fs.String("host", "", "the host to ban")
+		fs.Parse(args)
+		return report(c.Ban(ctx, &pb.BanRequest{Name: *name, Host: *host, Reason: strings.Join(fs.Args(), " ")}))
+	case "end":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: end <room> [reason]")
+		}
+		return report(c.EndGame(ctx, &pb.EndGameRequest{Room: args[0], Reason: strings.Join(args[1:], " ")}))
+	case "config":
+		if len(args) == 0 {
+			return fmt.Errorf("usage: config <room> [-tick d] [-wrap=bool] [-mode ffa|royale|teams] [-shrink n] [-teams n] [-friendly_fire=bool] [-scoring length|kills]")
+		}
+		return configure(ctx, c, args[0], args[1:])
+	case "announce":
+		fs := flag.NewFlagSet("announce", flag.ExitOnError)
+		room := fs.String("room", "", "the room to announce to, defaults to every room")
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return fmt.Errorf("usage: announce [-room room] <message>")
+		}
+		return report(c.Announce(ctx, &pb.AnnounceRequest{Room: *room, Message: strings.Join(fs.Args(), " ")}))
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+func runTournament(ctx context.Context, c pb.AdminClient, sc pb.SnekClient, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tournament create|add|show ...")
+	}
+	switch cmd, args := args[0], args[1:]; cmd {
+	case "create":
+		fs := flag.NewFlagSet("tournament create", flag.ExitOnError)
+		format := fs.String("format", "roundrobin", "either 'roundrobin' or 'elimination'")
+		start := fs.Duration("start", 0, "how long from now to start the tournament")
+		ticks := fs.Int64("ticks", 0, "how many ticks a match lasts, the server's default if 0")
+		fs.Parse(args)
+		if fs.NArg() < 1 {
+			return fmt.Errorf("usage: tournament create [flags] <name> <entrant>...")
+		}
+
+		cfg := &pb.TournamentConfig{Name: fs.Arg(0), MatchTicks: *ticks}
+		switch *format {
+		case "roundrobin":
+			cfg.Format = pb.TournamentFormat_ROUND_ROBIN
+		case "elimination":
+			cfg.Format = pb.TournamentFormat_SINGLE_ELIMINATION
+		default:
+			return fmt.Errorf("unknown format %q, must be 'roundrobin' or 'elimination'", *format)
+		}
+		if *start > 0 {
+			cfg.StartUnix = time.Now().Add(*start).Unix()
+		}
+		for _, e := range fs.Args()[1:] {
+			cfg.Entrants = append(cfg.Entrants, parseEntrant(e))
+		}
+		return report(c.CreateTournament(ctx, cfg))
+	case "add":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: tournament add <name> <entrant>")
+		}
+		return report(c.AddEntrant(ctx, &pb.AddEntrantRequest{Tournament: args[0], Entrant: parseEntrant(args[1])}))
+	case "show":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: tournament show <name>")
+		}
+		info, err := sc.Tournament(ctx, &pb.TournamentRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+		return showTournament(info)
+	}
+	return fmt.Errorf("unknown tournament command %q", args[0])
+}
+
+// parseEntrant parses a player's name, or name=url for a bot.
+func parseEntrant(s string) *pb.Entrant {
+	if i := strings.Index(s, "="); i >= 0 {
+		return &pb.Entrant{Name: s[:i], BotUrl: s[i+1:]}
+	}
+	return &pb.Entrant{Name: s}
+}
+
+func showTournament(info *pb.TournamentInfo) error {
+	fmt.Printf("%s, %s, %s\n", info.Name, info.Format, info.State)
+	if info.State == "scheduled" {
+		fmt.Printf("Starts at %s\n", time.Unix(info.StartUnix, 0).Format(time.RFC1123))
+	}
+	if info.Champion != "" {
+		fmt.Printf("Champion: %s\n", info.Champion)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "\nPLAYER\tPLAYED\tWON\tDRAWN\tLOST\tPOINTS\t")
+	for _, s := range info.Standings {
+		out := ""
+		if s.Eliminated {
+			out = "out"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.1f\t%s\n", s.Name, s.Played, s.Wins, s.Draws, s.Losses, s.Points, out)
+	}
+	fmt.Fprintln(w, "\nROUND\tROOM\tMATCH\tRESULT\t")
+	for _, m := range info.Matches {
+		result := "playing"
+		switch {
+		case m.Done && m.Winner == "":
+			result = "draw"
+		case m.Done:
+			result = m.Winner + " won"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t\n", m.Round, m.Room, strings.Join(m.Entrants, " vs "), result)
+	}
+	return w.Flush()
+}
+
+func listRooms(ctx context.Context, c pb.AdminClient) error {
+	resp, err := c.ListRooms(ctx, &pb.ListRoomsRequest{})
+	if err != nil {
+		return err
+	}
+	if len(resp.Rooms) == 0 {
+		fmt.Println("No rooms are open")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, r := range resp.Rooms {
+		fmt.Fprintf(w, "%s\ttick %d\tevery %dms\twrap %t\tmargin %d\t%s\n", r.Name, r.Tick, r.Config.GetTickMillis(), r.Config.GetWrap(), r.Config.GetMargin(), r.Config.GetMode())
+		for _, p := range r.Players {
+			name := p.Name
+			if name == "" {
+				name = "(anonymous)"
+			}
+			status := "alive"
+			if p.Dead {
+				status = "dead"
+			}
+			fmt.Fprintf(w, "  %d\t%s\t%s\t%s\tqueue %d\tviolations %d\n", p.Id, name, p.Addr, status, p.QueueDepth, p.Violations)
+		}
+	}
+	return w.Flush()
+}
+
+// configure changes only the settings that were passed, keeping the rest of the
+// room's current config.
+func configure(ctx context.Context, c pb.AdminClient, room string, args []string) error {
+	rooms, err := c.ListRooms(ctx, &pb.ListRoomsRequest{})
+	if err != nil {
+		return err
+	}
+	var cfg *pb.RoomConfig
+	for _, r := range rooms.Rooms {
+		if r.Name == room {
+			cfg = r.Config
+		}
+	}
+	if cfg == nil {
+		return fmt.Errorf("no room named %q", room)
+	}
+
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	tick := fs.Duration("tick", time.Duration(cfg.TickMillis)*time.Millisecond, "how often the sneks move")
+	wrap := fs.Bool("wrap", cfg.Wrap, "whether sneks wrap around the board")
+	margin := fs.Int("margin", int(cfg.Margin), "how many cells in from each edge of the board are out of play")
+	mode := fs.String("mode", "", "one of 'ffa', 'royale' or 'teams', defaults to the room's current mode")
+	shrink := fs.Int64("shrink", cfg.ShrinkTicks, "how many ticks it takes a battle royale arena to close in by a cell, 0 for the server's default")
+	teams := fs.Int("teams", int(cfg.Teams), "how many teams there are in a team game, 0 for 2")
+	friendlyFire := fs.Bool("friendly_fire", cfg.FriendlyFire, "whether running into a teammate kills you in a team game")
+	scoring := fs.String("scoring", "", "either 'length' or 'kills', defaults to the room's current scoring")
+	fs.Parse(args)
+
+	cfg.TickMillis = int64(*tick / time.Millisecond)
+	cfg.Wrap = *wrap
+	cfg.Margin = int32(*margin)
+	cfg.ShrinkTicks = *shrink
+	cfg.Teams = int32(*teams)
+	cfg.FriendlyFire = *friendlyFire
+	switch *scoring {
+	case "":
+	case "length":
+		cfg.Scoring = pb.TeamScoring_LENGTH
+	case "kills":
+		cfg.Scoring = pb.TeamScoring_KILLS
+	default:
+		return fmt.Errorf("unknown scoring %q, must be 'length' or 'kills'", *scoring)
+	}
+	switch *mode {
+	case "":
+	case "ffa":
+		cfg.Mode = pb.RoomMode_FREE_FOR_ALL
+	case "royale":
+		cfg.Mode = pb.RoomMode_BATTLE_ROYALE
+		// Nobody can wrap in a battle royale, so don't make people say so
+		cfg.Wrap = false
+	case "teams":
+		cfg.Mode = pb.RoomMode_TEAMS
+	default:
+		return fmt.Errorf("unknown mode %q, must be 'ffa', 'royale' or 'teams'", *mode)
+	}
+	return report(c.ConfigureRoom(ctx, &pb.ConfigureRoomRequest{Room: room, Config: cfg}))
+}
+
+func report(res *pb.AdminResult, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Printf("OK, %d snek(s) affected\n", res.Affected)
+	return nil
+}
+
+func dialCreds() (grpc.DialOption, error) {
+	if *caFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(*caFile, "")
+		if err != nil {
+			return nil, err
+		}
+		return grpc.WithTransportCredentials(creds), nil
+	}
+	if *useTLS {
+		return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})), nil
+	}
+	return grpc.WithInsecure(), nil
+}
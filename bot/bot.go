@@ -0,0 +1,189 @@
+// Package bot lets you write automated players for a snek server. It connects
+// to the server, keeps track of the board, and asks your Player which way to
+// go on every tick of the room's clock:
+//
+//	err := bot.Play(ctx, bot.Config{Addr: "localhost:6000"}, bot.MoveFunc(func(s *bot.State) rules.Direction {
+//		for _, d := range rules.Directions {
+//			if s.Safe(d) {
+//				return d
+//			}
+//		}
+//		return s.You.Dir
+//	}))
+package bot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"strconv"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Player decides where a bot goes next.
+type Player interface {
+	// Move is called once a tick with the current state of the board, and
+	// returns which way to go. Turning back on yourself isn't allowed, so a move
+	// in the opposite direction is ignored. The state shouldn't be kept around
+	// after Move returns.
+	Move(*State) rules.Direction
+}
+
+// MoveFunc lets an ordinary function be used as a Player.
+type MoveFunc func(*State) rules.Direction
+
+func (f MoveFunc) Move(s *State) rules.Direction {
+	return f(s)
+}
+
+// Config says where and how a bot connects.
+type Config struct {
+	// The address of the server, e.g. "localhost:6000"
+	Addr string
+	// The room to play in, the server's main arena if empty
+	Room string
+	// Credentials, if the server needs them. Either a token, or a user and
+	// password.
+	Token, User, Password string
+	// The name to play as, on servers that don't need credentials
+	Name string
+	// The team to play on in team games, the server picks if it's 0
+	Team int
+	// How to dial the server, insecurely if empty
+	DialOptions []grpc.DialOption
+	// Called with anything the server has to say, like why we're being kicked
+	OnNotice func(string)
+}
+
+// ErrDied is returned by Play when the bot's snek dies.
+var ErrDied = errors.New("bot: snek died")
+
+// Play connects to the server and plays until the bot's snek dies, the server
+// hangs up, or the context is done.
+func Play(ctx context.Context, cfg Config, p Player) error {
+	opts := cfg.DialOptions
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	conn, err := grpc.Dial(cfg.Addr, opts...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stream, err := pb.NewSnekClient(conn).Update(metadata.NewOutgoingContext(ctx, cfg.metadata()))
+	if err != nil {
+		return err
+	}
+	defer stream.CloseSend()
+
+	g := rules.NewGame(rules.NewBoard(false), rand.New(rand.NewSource(time.Now().UnixNano())))
+	others := make(map[int32]*rules.Snek)
+	teams := make(map[int32]int32)
+	var you int32
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case resp.Id == 0:
+		case resp.Ack:
+			continue
+		case resp.Dead:
+			if resp.Id == you {
+				// The server says we ran into something
+				return ErrDied
+			}
+			delete(others, resp.Id)
+			continue
+		default:
+			o, ok := others[resp.Id]
+			if !ok {
+				o = &rules.Snek{}
+				others[resp.Id] = o
+			}
+			o.Follow(locFromPB(resp.NewHead), locFromPB(resp.OldTail))
+			continue
+		}
+
+		if resp.Config != nil {
+			g.Wrap = resp.Config.Wrap
+		}
+		if resp.You != 0 {
+			you = resp.You
+		}
+		if resp.Spawn != nil {
+			g.Respawn(locFromPB(resp.Spawn))
+		}
+		if len(resp.Teams) > 0 {
+			teams = make(map[int32]int32)
+		}
+		for _, t := range resp.Teams {
+			for _, id := range t.Members {
+				teams[id] = t.Number
+			}
+		}
+		if resp.Notice != "" && cfg.OnNotice != nil {
+			cfg.OnNotice(resp.Notice)
+		}
+		if resp.Tick <= g.Tick {
+			continue
+		}
+
+		// Line ourselves up with the server's clock, then move
+		g.Tick = resp.Tick - 1
+		if !g.CloseIn(int(resp.Margin)) {
+			stream.Send(&pb.UpdateRequest{Tick: g.Tick, Dead: true})
+			return ErrDied
+		}
+		s := &State{Board: g.Board, Tick: resp.Tick, You: g.Snek, Others: others, Food: g.Food, Teams: teams, ID: you}
+		m, ok := g.Step(p.Move(s))
+		if !ok {
+			stream.Send(&pb.UpdateRequest{Tick: g.Tick, Dead: true})
+			return ErrDied
+		}
+		err = stream.Send(&pb.UpdateRequest{
+			NewHead: &pb.Loc{X: int32(m.Head.X), Y: int32(m.Head.Y)},
+			OldTail: &pb.Loc{X: int32(m.Tail.X), Y: int32(m.Tail.Y)},
+			Tick:    g.Tick,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func locFromPB(l *pb.Loc) rules.Loc {
+	return rules.Loc{X: int(l.GetX()), Y: int(l.GetY())}
+}
+
+func (cfg Config) metadata() metadata.MD {
+	var kv []string
+	if cfg.Room != "" {
+		kv = append(kv, "room", cfg.Room)
+	}
+	if cfg.Token != "" {
+		kv = append(kv, "authorization", "Bearer "+cfg.Token)
+	}
+	if cfg.User != "" {
+		kv = append(kv, "username", cfg.User, "password", cfg.Password)
+	}
+	if cfg.Name != "" {
+		kv = append(kv, "name", cfg.Name)
+	}
+	if cfg.Team != 0 {
+		kv = append(kv, "team", strconv.Itoa(cfg.Team))
+	}
+	return metadata.Pairs(kv...)
+}
@@ -0,0 +1,30 @@
+// Command greedy plays bot.Greedy, which heads straight for its food as long as
+// it's safe to.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/bcspragu/Snek/bot"
+)
+
+var (
+	addr = flag.String("addr", "localhost:6000", "the address of the snek server")
+	room = flag.String("room", "", "the room to play in")
+	name = flag.String("name", "greedy", "the name to play as")
+)
+
+func main() {
+	flag.Parse()
+	cfg := bot.Config{
+		Addr:     *addr,
+		Room:     *room,
+		Name:     *name,
+		OnNotice: func(n string) { log.Printf("Server says: %s", n) },
+	}
+	if err := bot.Play(context.Background(), cfg, bot.Greedy); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+// Command wanderer plays bot.Wanderer, which turns at random, but grabs its
+// food whenever it's right next to it.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/bcspragu/Snek/bot"
+	"github.com/bcspragu/Snek/rules"
+)
+
+var (
+	addr = flag.String("addr", "localhost:6000", "the address of the snek server")
+	room = flag.String("room", "", "the room to play in")
+	turn = flag.Float64("turn", 0.1, "how likely we are to turn on any given tick")
+)
+
+func main() {
+	flag.Parse()
+	w := &bot.Wanderer{Rand: rand.New(rand.NewSource(time.Now().UnixNano())), Turn: *turn}
+
+	// Bots can build on each other, this one only wanders when its food isn't
+	// one step away
+	p := bot.MoveFunc(func(s *bot.State) rules.Direction {
+		for _, d := range rules.Directions {
+			if n, ok := s.Next(s.You.Head(), d); ok && n == s.Food && s.Safe(d) {
+				return d
+			}
+		}
+		return w.Move(s)
+	})
+	if err := bot.Play(context.Background(), bot.Config{Addr: *addr, Room: *room}, p); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"math/rand"
+
+	"github.com/bcspragu/Snek/rules"
+)
+
+// Greedy heads straight for its food, as long as it's safe to.
+var Greedy = MoveFunc(func(s *State) rules.Direction {
+	head := s.You.Head()
+	best, bestDist := s.You.Dir, -1
+	for _, d := range rules.Directions {
+		if !s.Safe(d) {
+			continue
+		}
+		n, _ := s.Next(head, d)
+		if dist := s.Distance(n, s.Food); bestDist < 0 || dist < bestDist {
+			best, bestDist = d, dist
+		}
+	}
+	return best
+})
+
+// Wanderer mostly goes straight, and turns at random when it has to or feels
+// like it.
+type Wanderer struct {
+	Rand *rand.Rand
+	// How likely it is to turn on any given tick
+	Turn float64
+}
+
+func (w *Wanderer) Move(s *State) rules.Direction {
+	if s.Safe(s.You.Dir) && w.Rand.Float64() >= w.Turn {
+		return s.You.Dir
+	}
+	var safe []rules.Direction
+	for _, d := range rules.Directions {
+		if s.Safe(d) {
+			safe = append(safe, d)
+		}
+	}
+	if len(safe) == 0 {
+		// Nowhere to go, so go out in a straight line
+		return s.You.Dir
+	}
+	return safe[w.Rand.Intn(len(safe))]
+}
@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"github.com/bcspragu/Snek/rules"
+)
+
+// State is everything a bot knows about the game when it's asked to move.
+type State struct {
+	rules.Board
+	Tick int64
+	// Our own snek
+	You *rules.Snek
+	// Everyone else in the room, by ID. We only know where they've been since
+	// we joined, so sneks that were already playing may look shorter than they
+	// are until they've moved their whole length.
+	Others map[int32]*rules.Snek
+	// Our food, every snek has their own
+	Food rules.Loc
+	// Our snek's ID, and which team every snek is on by ID in team games
+	ID    int32
+	Teams map[int32]int32
+}
+
+// Teammate returns whether the snek with the given ID is on our team.
+func (s *State) Teammate(id int32) bool {
+	t, ok := s.Teams[s.ID]
+	return ok && s.Teams[id] == t
+}
+
+// Occupied returns whether any snek, including our own, is on the cell.
+func (s *State) Occupied(l rules.Loc) bool {
+	if s.You.Covers(l) {
+		return true
+	}
+	for _, o := range s.Others {
+		if o.Covers(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// Safe returns whether our snek can go in the given direction without dying
+// or running into anyone.
+func (s *State) Safe(d rules.Direction) bool {
+	if d == s.You.Dir.Opposite() {
+		return false
+	}
+	n, ok := s.Next(s.You.Head(), d)
+	return ok && !s.Occupied(n)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Snek/levels"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// progress is how far the player has gotten through the campaign.
+type progress struct {
+	// How many levels they've beaten, they can play any level up to the one
+	// after that
+	Completed int `json:"completed"`
+}
+
+// playCampaign plays through the campaign's levels from the first one the
+// player hasn't beaten, or the one they asked for with -level, until they quit.
+func playCampaign(evChan chan *termbox.Event) error {
+	lvls, err := levels.Campaign()
+	if err != nil {
+		return err
+	}
+	fn := homeFile(*progressPath, ".snek_campaign.json")
+	p := &progress{}
+	if err := loadJSON(fn, p); err != nil {
+		return err
+	}
+
+	n := p.Completed
+	if *level > 0 {
+		if *level > len(lvls) {
+			return fmt.Errorf("there are only %d levels in the campaign", len(lvls))
+		}
+		if *level > p.Completed+1 {
+			return fmt.Errorf("you haven't unlocked level %d yet, beat level %d first", *level, p.Completed+1)
+		}
+		n = *level - 1
+	}
+	if n >= len(lvls) {
+		// They've beaten everything, start them over from the top
+		n = 0
+	}
+
+	for {
+		lvl := lvls[n]
+		drawLines([]string{
+			fmt.Sprintf("Level %d of %d: %s", n+1, len(lvls), lvl.Name),
+			"",
+			lvl.Goal.String(),
+			"",
+			"Press Enter to start, any other key to quit",
+		})
+		if !waitForEnter(evChan) {
+			return nil
+		}
+
+		if !run(evChan, lvl) {
+			// They quit partway through
+			return nil
+		}
+
+		var lines []string
+		if game.won {
+			if n+1 > p.Completed {
+				p.Completed = n + 1
+				if err := saveJSON(fn, p); err != nil {
+					return err
+				}
+			}
+			n++
+			if n == len(lvls) {
+				drawLines([]string{
+					"You beat the campaign!",
+					"",
+					"Press any key to quit",
+				})
+				waitForEnter(evChan)
+				return nil
+			}
+			lines = []string{"Level complete!", "", "Press Enter for the next level, any other key to quit"}
+		} else {
+			msg := "You died!"
+			if game.notice != "" {
+				msg = game.notice
+			}
+			lines = []string{msg, "", "Press Enter to try again, any other key to quit"}
+		}
+		drawLines(lines)
+		if !waitForEnter(evChan) {
+			return nil
+		}
+	}
+}
+
+// waitForEnter waits for a key, and returns whether it was Enter.
+func waitForEnter(evChan chan *termbox.Event) bool {
+	for ev := range evChan {
+		if ev.Type == termbox.EventKey {
+			return ev.Key == termbox.KeyEnter
+		}
+	}
+	return false
+}
+
+// checkGoal checks whether we've beaten the level we're playing, or can't
+// anymore, and returns true if either is the case.
+func (g *Game) checkGoal() bool {
+	goal := g.level.Goal
+	switch goal.Kind {
+	case levels.Length:
+		g.won = len(g.play.Snek.Body) >= goal.N
+	case levels.Food:
+		g.won = g.eaten >= goal.N
+		if !g.won && g.play.Tick >= goal.Ticks {
+			g.notice = "Out of time!"
+			g.drawHUD()
+			return true
+		}
+	case levels.Survive:
+		g.won = g.play.Tick >= goal.Ticks
+	case levels.Endless:
+		return false
+	}
+	if g.won {
+		g.notice = "Level complete!"
+	}
+	g.drawHUD()
+	return g.won
+}
+
+// drawGoal draws the level we're on and how close we are to beating it into
+// the top border.
+func (g *Game) drawGoal() {
+	if g.level == nil || g.level.Goal.Kind == levels.Endless {
+		return
+	}
+	goal := g.level.Goal
+	var str string
+	switch goal.Kind {
+	case levels.Length:
+		str = fmt.Sprintf("Length %d/%d", len(g.play.Snek.Body), goal.N)
+	case levels.Food:
+		str = fmt.Sprintf("Food %d/%d  Ticks left %d", g.eaten, goal.N, goal.Ticks-g.play.Tick)
+	case levels.Survive:
+		str = fmt.Sprintf("Ticks left %d", goal.Ticks-g.play.Tick)
+	}
+	str = fmt.Sprintf(" %s  %s ", g.level.Name, str)
+	for i, r := range str {
+		termbox.SetCell(g.bbox.Left()+2+i, g.bbox.Top(), r, termbox.ColorWhite, termbox.ColorDefault)
+	}
+}
+
+// drawWalls draws the level's walls.
+func (g *Game) drawWalls() {
+	for l := range g.play.Walls {
+		g.setCell(l, '▓', termbox.ColorWhite)
+	}
+}
+
+// startLevel keeps track of the level we're playing, which the game was started
+// on.
+func (g *Game) startLevel(lvl *levels.Level) {
+	g.level = lvl
+	if lvl.Goal.Kind == levels.Endless {
+		// There's nothing to track, but let them know which level it is
+		g.notice = lvl.Name
+	}
+}
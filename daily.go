@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bcspragu/Snek/levels"
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// How long the daily challenge lasts
+const dailyTime = levels.DailyTicks * localTick
+
+// dailyLog is every daily challenge we've played, by date, and how long we got.
+type dailyLog map[string]int
+
+// playDaily plays today's daily challenge, which is the same game for everyone
+// playing it today, and sends how we did to the server. Only the first attempt
+// each day counts, later ones are just practice.
+func playDaily(evChan chan *termbox.Event) error {
+	day := time.Now().UTC()
+	date := day.Format("2006-01-02")
+	lvl := levels.Daily(day)
+
+	fn := homeFile(*dailyPath, ".snek_daily.json")
+	played := make(dailyLog)
+	if err := loadJSON(fn, &played); err != nil {
+		return err
+	}
+	prev, practice := played[date]
+
+	lines := []string{
+		lvl.Name,
+		"",
+		fmt.Sprintf("Grow as long as you can in %s", dailyTime),
+		"",
+	}
+	if practice {
+		lines = append(lines, fmt.Sprintf("You've already played today and got to a length of %d,", prev), "so this one's just practice", "")
+	}
+	lines = append(lines, "Press Enter to start, any other key to quit")
+	drawLines(lines)
+	if !waitForEnter(evChan) {
+		return nil
+	}
+
+	var client pb.SnekClient
+	if *addr != "" {
+		creds, err := dialCreds()
+		if err != nil {
+			return err
+		}
+		conn, err := grpc.Dial(*addr, creds)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		client = pb.NewSnekClient(conn)
+	}
+
+	// Starting is what uses up today's attempt, quitting partway through
+	// doesn't get you another one
+	var notCounted string
+	if !practice {
+		played[date] = 0
+		if err := saveJSON(fn, played); err != nil {
+			return err
+		}
+		if client != nil {
+			if _, err := client.StartDaily(streamContext(), &pb.DailyRequest{Date: date}); err != nil {
+				notCounted = status.Convert(err).Message()
+			}
+		}
+	}
+	over := run(evChan, lvl)
+	length := len(game.play.Snek.Body)
+
+	title := "You quit"
+	switch {
+	case over && game.clock.left <= 0:
+		title = "Time's up!"
+	case over:
+		title = "You died!"
+	}
+	lines = []string{title, fmt.Sprintf("You got to a length of %d", length), ""}
+	if practice {
+		lines = append(lines, "This was practice, so it doesn't count", "")
+	} else {
+		played[date] = length
+		if err := saveJSON(fn, played); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case client == nil:
+		lines = append(lines, "Set -addr to see how everyone else did")
+	case notCounted != "":
+		lines = append(lines, notCounted, "so the server didn't count this one", "")
+		lines = append(lines, dailyResults(client, date, 0, nil)...)
+	case practice:
+		lines = append(lines, dailyResults(client, date, 0, nil)...)
+	default:
+		lines = append(lines, dailyResults(client, date, length, game.moves)...)
+	}
+	lines = append(lines, "", "Press any key to quit")
+	drawLines(lines)
+	waitForEnter(evChan)
+	return nil
+}
+
+// recordMove remembers which way we went this tick in the daily challenge, so
+// the server can replay our game to check the result.
+func (g *Game) recordMove() {
+	if !*daily {
+		return
+	}
+	for i, d := range rules.Directions {
+		if d == g.play.Snek.Dir {
+			g.moves = append(g.moves, pb.PhoneType(i))
+		}
+	}
+}
+
+// dailyResults sends our result to the server, if we have moves to send, and
+// returns lines showing how everyone's done today.
+func dailyResults(client pb.SnekClient, date string, length int, moves []pb.PhoneType) []string {
+	var lines []string
+	rank := int32(0)
+	if moves != nil {
+		st, err := client.SubmitDaily(streamContext(), &pb.DailyResult{Date: date, Length: int32(length), Moves: moves})
+		if err != nil {
+			lines = append(lines, status.Convert(err).Message(), "")
+		} else {
+			rank = st.Rank
+			lines = append(lines, fmt.Sprintf("You placed #%d of %d so far today", st.Rank, st.Players), "")
+		}
+	}
+
+	resp, err := client.DailyLeaderboard(streamContext(), &pb.DailyRequest{Date: date, Limit: 10})
+	if err != nil {
+		return append(lines, status.Convert(err).Message())
+	}
+	if len(resp.Scores) == 0 {
+		return append(lines, "Nobody's on the leaderboard yet")
+	}
+	lines = append(lines, fmt.Sprintf("%4s  %-20s  %6s", "#", "Name", "Length"))
+	for _, s := range resp.Scores {
+		mark := " "
+		if s.Rank == rank {
+			mark = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s%3d  %-20.20s  %6d", mark, s.Rank, s.Name, s.Length))
+	}
+	return lines
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bcspragu/Snek/levels"
+	"github.com/bcspragu/Snek/rules"
+)
+
+// towardFood returns the first step of the shortest way to the food, or keeps
+// going the same way if there isn't one.
+func towardFood(g *rules.Game) rules.Direction {
+	first := map[rules.Loc]rules.Direction{}
+	var queue []rules.Loc
+	for _, d := range rules.Directions {
+		if d == g.Snek.Dir.Opposite() {
+			continue
+		}
+		if n, ok := g.Next(g.Snek.Head(), d); ok && !g.Snek.Covers(n) && !g.HazardAt(n) {
+			if _, seen := first[n]; !seen {
+				first[n] = d
+				queue = append(queue, n)
+			}
+		}
+	}
+	for len(queue) > 0 {
+		l := queue[0]
+		queue = queue[1:]
+		if l == g.Food {
+			return first[l]
+		}
+		for _, d := range rules.Directions {
+			if n, ok := g.Next(l, d); ok && !g.Snek.Covers(n) {
+				if _, seen := first[n]; !seen {
+					first[n] = first[l]
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return g.Snek.Dir
+}
+
+// The server checks daily challenge results by replaying the moves we send it,
+// so the game we play has to be the game it replays.
+func TestDailyMovesReplay(t *testing.T) {
+	defer func(d bool) { *daily = d }(*daily)
+	*daily = true
+
+	for _, date := range []string{"2026-10-17", "2026-10-18", "2026-10-19"} {
+		day, _ := time.Parse("2006-01-02", date)
+		lvl := levels.Daily(day)
+		g := newGame(false, lvl)
+		for i := 0; i < 400; i++ {
+			g.nextDirs = append(g.nextDirs, towardFood(g.play))
+			if !g.update() {
+				break
+			}
+		}
+		if len(g.play.Snek.Body) <= rules.StartLength {
+			t.Fatalf("%s: the snek never ate, so the test doesn't show much", date)
+		}
+
+		var dirs []rules.Direction
+		for _, mv := range g.moves {
+			dirs = append(dirs, rules.Directions[mv])
+		}
+		replay, taken := levels.Daily(day).Replay(dirs)
+		if taken != len(dirs) {
+			t.Errorf("%s: the replay died after %d of %d moves", date, taken, len(dirs))
+		}
+		if got, want := len(replay.Snek.Body), len(g.play.Snek.Body); got != want {
+			t.Errorf("%s: the replay got to a length of %d, we got to %d", date, got, want)
+		}
+	}
+}
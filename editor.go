@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bcspragu/Snek/levels"
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// tool is what the level editor paints onto the board.
+type tool int
+
+const (
+	wallTool tool = iota
+	portalTool
+	startTool
+	foodTool
+)
+
+var (
+	toolNames = map[tool]string{
+		wallTool:   "wall",
+		portalTool: "portal",
+		startTool:  "start",
+		foodTool:   "food spawn",
+	}
+	toolKeys = map[rune]tool{
+		'w': wallTool,
+		'p': portalTool,
+		's': startTool,
+		'f': foodTool,
+	}
+	dirArrows = map[rules.Direction]rune{
+		rules.Up:    '↑',
+		rules.Down:  '↓',
+		rules.Left:  '←',
+		rules.Right: '→',
+	}
+)
+
+// editor is the level editor, which paints a level's walls, portals, start and
+// food spawns onto the board. The level's name and goal are kept from the file,
+// they're edited by hand.
+type editor struct {
+	fn  string
+	lvl *levels.Level
+	// Only used to draw the board with
+	g      *Game
+	cursor rules.Loc
+	tool   tool
+	// The first end of the portal we're placing, until we place the other
+	portal *rules.Loc
+	saved  bool
+	status string
+}
+
+// editLevel opens a level file in the editor, or starts a new level if the
+// file doesn't exist yet, and runs the editor until it's quit.
+func editLevel(evChan chan *termbox.Event, fn string) error {
+	e := &editor{fn: fn, saved: true}
+	f, err := os.Open(fn)
+	switch {
+	case os.IsNotExist(err):
+		e.lvl = levels.New(strings.TrimSuffix(filepath.Base(fn), filepath.Ext(fn)))
+		e.saved = false
+	case err != nil:
+		return err
+	default:
+		e.lvl, err = levels.Parse(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", fn, err)
+		}
+	}
+	e.cursor = e.lvl.Start
+
+	termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+	e.draw()
+	for ev := range evChan {
+		e.status = ""
+		switch ev.Type {
+		case termbox.EventMouse:
+			l, ok := e.cellAt(ev.MouseX, ev.MouseY)
+			if !ok {
+				continue
+			}
+			e.cursor = l
+			switch ev.Key {
+			case termbox.MouseLeft:
+				e.paint(l)
+			case termbox.MouseRight:
+				e.erase(l)
+			}
+		case termbox.EventKey:
+			if d, ok := keyMap[ev.Key]; ok {
+				e.move(d)
+				break
+			}
+			switch {
+			case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyCtrlC || ev.Ch == 'q':
+				return nil
+			case ev.Key == termbox.KeyCtrlS:
+				if err := e.save(); err != nil {
+					e.status = fmt.Sprintf("Failed to save: %v", err)
+				} else {
+					e.status = "Saved to " + e.fn
+				}
+			case ev.Key == termbox.KeySpace || ev.Key == termbox.KeyEnter:
+				e.paint(e.cursor)
+			case ev.Ch == 'x' || ev.Key == termbox.KeyDelete || ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+				e.erase(e.cursor)
+			case ev.Ch == 'd':
+				e.turn()
+			case ev.Ch == 't':
+				if e.portal != nil {
+					e.status = "Place the other end of the portal first"
+					break
+				}
+				termbox.SetInputMode(termbox.InputEsc)
+				run(evChan, e.lvl)
+				termbox.SetInputMode(termbox.InputEsc | termbox.InputMouse)
+				e.status = "Back from playing the level"
+			default:
+				if t, ok := toolKeys[ev.Ch]; ok {
+					e.tool = t
+				}
+			}
+		}
+		e.draw()
+	}
+	return nil
+}
+
+// cellAt returns the board cell at a spot on the screen, and false if it isn't
+// on the board.
+func (e *editor) cellAt(x, y int) (rules.Loc, bool) {
+	l := rules.Loc{X: x/2 - e.g.bbox.Left()/2 - 1, Y: y - e.g.bbox.Top() - 1}
+	return l, e.lvl.Board().InBounds(l)
+}
+
+// move moves the cursor a cell, stopping at the edges of the board.
+func (e *editor) move(d rules.Direction) {
+	l := rules.Loc{X: e.cursor.X + d.X, Y: e.cursor.Y + d.Y}
+	if e.lvl.Board().InBounds(l) {
+		e.cursor = l
+	}
+}
+
+// turn changes which way the snek starts out heading.
+func (e *editor) turn() {
+	for i, d := range rules.Directions {
+		if d == e.lvl.Dir {
+			// Go round clockwise: up, right, down, left
+			e.lvl.Dir = []rules.Direction{rules.Right, rules.Left, rules.Up, rules.Down}[i]
+			break
+		}
+	}
+	e.saved = false
+}
+
+// erase clears everything off a cell, including the other end of a portal.
+func (e *editor) erase(l rules.Loc) {
+	lvl := e.lvl
+	delete(lvl.Walls, l)
+	if exit, ok := lvl.Portals[l]; ok {
+		delete(lvl.Portals, l)
+		delete(lvl.Portals, exit)
+	}
+	if e.portal != nil && *e.portal == l {
+		e.portal = nil
+	}
+	var spawns []rules.Loc
+	for _, s := range lvl.FoodSpawns {
+		if s != l {
+			spawns = append(spawns, s)
+		}
+	}
+	lvl.FoodSpawns = spawns
+	var hazards []rules.Hazard
+	for _, z := range lvl.Hazards {
+		if z.At != l {
+			hazards = append(hazards, z)
+		}
+	}
+	lvl.Hazards = hazards
+	e.saved = false
+}
+
+// paint puts whatever the current tool is onto a cell, replacing what was
+// there. The snek's start can't be painted over, it has to be moved.
+func (e *editor) paint(l rules.Loc) {
+	if l == e.lvl.Start {
+		e.status = "That's where the snek starts, move the start first"
+		return
+	}
+	switch e.tool {
+	case wallTool:
+		e.erase(l)
+		e.lvl.Walls[l] = true
+	case portalTool:
+		if e.portal == nil && len(e.lvl.Portals)/2 >= 'z'-'a'+1 {
+			e.status = "That's as many portals as a level can have"
+			return
+		}
+		e.erase(l)
+		if e.portal == nil {
+			e.portal = &l
+			return
+		}
+		e.lvl.Portals[*e.portal], e.lvl.Portals[l] = l, *e.portal
+		e.portal = nil
+	case startTool:
+		e.erase(l)
+		e.lvl.Start = l
+	case foodTool:
+		e.erase(l)
+		e.lvl.FoodSpawns = append(e.lvl.FoodSpawns, l)
+	}
+	e.saved = false
+}
+
+func (e *editor) save() error {
+	if e.portal != nil {
+		return fmt.Errorf("place the other end of the portal first")
+	}
+	var buf bytes.Buffer
+	if err := levels.Write(&buf, e.lvl); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(e.fn, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	e.saved = true
+	return nil
+}
+
+func (e *editor) draw() {
+	lvl := e.lvl
+	e.g = &Game{bbox: calcBbox()}
+	g := e.g
+	// Set up a game for the drawing helpers to draw from
+	g.play = &rules.Game{Board: lvl.Board(), Snek: rules.NewSnek(lvl.Start, 1)}
+	for _, z := range lvl.Hazards {
+		z := z
+		g.play.Hazards = append(g.play.Hazards, &z)
+	}
+
+	g.drawBorder()
+	g.drawWalls()
+	g.drawPortals()
+	g.drawHazards()
+	if e.portal != nil {
+		g.setCell(*e.portal, '◊', termbox.ColorWhite)
+	}
+	for _, l := range lvl.FoodSpawns {
+		x, y := g.screenPos(l)
+		termbox.SetCell(x+1, y, '*', termbox.ColorYellow, termbox.ColorDefault)
+	}
+	x, y := g.screenPos(lvl.Start)
+	termbox.SetCell(x, y, '@', termbox.ColorGreen, termbox.ColorDefault)
+	termbox.SetCell(x+1, y, dirArrows[lvl.Dir], termbox.ColorGreen, termbox.ColorDefault)
+
+	// Show the cursor by flipping the colours of the cell it's on
+	x, y = g.screenPos(e.cursor)
+	w, h := termbox.Size()
+	buf := termbox.CellBuffer()
+	for i := 0; i < 2; i++ {
+		// On a small terminal the cursor can be off screen
+		if x+i < 0 || x+i >= w || y < 0 || y >= h {
+			continue
+		}
+		c := buf[y*w+x+i]
+		termbox.SetCell(x+i, y, c.Ch, c.Fg|termbox.AttrReverse, c.Bg)
+	}
+
+	if e.portal != nil && e.status == "" {
+		e.status = "Now place the other end of the portal"
+	}
+	unsaved := ""
+	if !e.saved {
+		unsaved = " (unsaved)"
+	}
+	str := fmt.Sprintf(" %s%s  Tool: %s  %d,%d  %s ", lvl.Name, unsaved, toolNames[e.tool], e.cursor.X, e.cursor.Y, e.status)
+	for i, r := range []rune(str) {
+		termbox.SetCell(g.bbox.Left()+2+i, g.bbox.Top(), r, termbox.ColorWhite, termbox.ColorDefault)
+	}
+	drawString(g.bbox.CenterX(), g.bbox.Bottom(), " w/p/s/f: tool  space: paint  x: erase  d: turn  t: test  ^S: save  q: quit ")
+	termbox.Flush()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// How many ticks apart new hazards show up in survival
+const survivalEvery = 150
+
+// The hazards that show up in survival, in the order they do
+var survivalHazards = []rules.HazardKind{rules.Ball, rules.Ball, rules.Patrol, rules.Hunter}
+
+func (g *Game) drawHazards() {
+	for _, z := range g.play.Hazards {
+		x, y := g.screenPos(z.At)
+		switch z.Kind {
+		case rules.Ball:
+			termbox.SetCell(x+1, y, '●', termbox.ColorRed, termbox.ColorDefault)
+		case rules.Patrol:
+			g.setCell(z.At, '▓', termbox.ColorYellow)
+		case rules.Hunter:
+			termbox.SetCell(x+1, y, '◆', termbox.ColorRed|termbox.AttrBold, termbox.ColorDefault)
+		}
+	}
+}
+
+// hazardCells returns where every hazard is, so we can clear them once they've
+// moved.
+func (g *Game) hazardCells() []rules.Loc {
+	var locs []rules.Loc
+	for _, z := range g.play.Hazards {
+		locs = append(locs, z.At)
+	}
+	return locs
+}
+
+// redrawHazards clears the cells the hazards were on, putting back anything
+// they were covering up, and draws them where they are now.
+func (g *Game) redrawHazards(was []rules.Loc) {
+	for _, l := range was {
+		if !g.play.Snek.Covers(l) {
+			g.clearTail(l)
+		}
+	}
+	g.drawFood()
+	g.drawBonus()
+	g.drawHazards()
+}
+
+// spawnHazards brings in a new hazard every so often in survival, so the
+// longer you last the harder it gets.
+func (g *Game) spawnHazards() {
+	if !*survival {
+		return
+	}
+	if g.play.Tick%survivalEvery == 0 {
+		n := int(g.play.Tick/survivalEvery) - 1
+		kind := survivalHazards[n%len(survivalHazards)]
+		if g.play.SpawnHazard(kind) != nil {
+			g.notice = fmt.Sprintf("A %s showed up!", kind)
+			g.drawHazards()
+		}
+	}
+	g.drawHUD()
+}
+
+// drawSurvival draws how long we've lasted in survival into the top border.
+func (g *Game) drawSurvival() {
+	if !*survival {
+		return
+	}
+	str := fmt.Sprintf(" Survived %d ticks  Hazards %d ", g.play.Tick, len(g.play.Hazards))
+	for i, r := range str {
+		termbox.SetCell(g.bbox.Left()+2+i, g.bbox.Top(), r, termbox.ColorWhite, termbox.ColorDefault)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	termbox "github.com/nsf/termbox-go"
+	"google.golang.org/grpc"
+)
+
+const (
+	// How many players we show on the leaderboard
+	leaderboardSize = 20
+	// How often we ask the server for new ratings
+	leaderboardRefresh = 5 * time.Second
+)
+
+// showLeaderboard draws the server's highest rated players until a key is
+// pressed.
+func showLeaderboard(evChan chan *termbox.Event) error {
+	if *addr == "" {
+		return fmt.Errorf("-leaderboard needs a server to show, set -addr")
+	}
+	creds, err := dialCreds()
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.Dial(*addr, creds)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := pb.NewSnekClient(conn)
+
+	t := time.NewTicker(leaderboardRefresh)
+	defer t.Stop()
+	for {
+		resp, err := client.Leaderboard(streamContext(), &pb.LeaderboardRequest{Limit: leaderboardSize})
+		if err != nil {
+			return err
+		}
+		drawLeaderboard(resp.Players)
+
+		select {
+		case ev := <-evChan:
+			if ev.Type == termbox.EventKey {
+				return nil
+			}
+		case <-t.C:
+		}
+	}
+}
+
+func drawLeaderboard(players []*pb.PlayerProfile) {
+	lines := []string{
+		"Leaderboard",
+		"",
+		fmt.Sprintf("%4s  %-20s  %6s  %9s", "#", "Name", "Rating", "W-L"),
+	}
+	for _, p := range players {
+		wl := fmt.Sprintf("%d-%d", p.Wins, p.Losses)
+		lines = append(lines, fmt.Sprintf("%4d  %-20.20s  %6.0f  %9s", p.Rank, p.Name, p.Rating, wl))
+	}
+	if len(players) == 0 {
+		lines = append(lines, "Nobody has been rated yet")
+	}
+	lines = append(lines, "", "Press any key to quit")
+	drawLines(lines)
+}
+
+// drawLines draws a table in the middle of an otherwise empty screen.
+func drawLines(lines []string) {
+	termbox.Clear(termbox.ColorDefault, termbox.ColorDefault)
+	// drawString centers every line on its own, so pad them all out to the same
+	// width to keep the columns lined up
+	width := 0
+	for _, l := range lines {
+		if len(l) > width {
+			width = len(l)
+		}
+	}
+	for i, l := range lines {
+		lines[i] = fmt.Sprintf("%-*s", width, l)
+	}
+
+	w, h := termbox.Size()
+	drawString(w/2, h/2, lines...)
+	termbox.Flush()
+}
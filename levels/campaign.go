@@ -0,0 +1,378 @@
+package levels
+
+// The campaign's levels, in the order they're played. The boards are as big
+// as the server's, so the maps are too.
+var campaign = []string{
+	`name: First Steps
+goal: length 20
+start: right
+map:
+################################################
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#...................@..........................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+################################################
+`,
+	`name: The Box
+goal: food 5 in 600
+start: right
+map:
+################################################
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#...........##########....##########...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#..............................................#
+#..............................................#
+#.................@............................#
+#..............................................#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........#......................#...........#
+#...........##########....##########...........#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+################################################
+`,
+	`name: Pillars
+goal: length 30
+start: up
+map:
+################################################
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.....##......##......##......##......##.......#
+#.....##......##......##......##......##.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.....##......##......##......##......##.......#
+#.....##......##......##......##......##.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.....##......##......##......##......##.......#
+#.....##......##......##......##......##.......#
+#..............................................#
+#.......................@......................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.....##......##......##......##......##.......#
+#.....##......##......##......##......##.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.....##......##......##......##......##.......#
+#.....##......##......##......##......##.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+################################################
+`,
+	`name: Corridors
+goal: survive 1000
+start: right
+map:
+################################################
+#..............................................#
+#..............................................#
+#..............................................#
+#.........@....................................#
+#..............................................#
+#..............................................#
+#..............................................#
+########################################.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.......########################################
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+########################################.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.......########################################
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+########################################.......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+################################################
+`,
+	`name: The Cross
+goal: length 40
+start: right
+wrap: true
+map:
+................................................
+................................................
+................................................
+................................................
+............@...................................
+................................................
+................................................
+................................................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+........################################........
+........################################........
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+.......................##.......................
+................................................
+................................................
+................................................
+................................................
+................................................
+................................................
+................................................
+................................................
+`,
+	`name: Wormholes
+goal: food 8 in 1500
+start: right
+map:
+################################################
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#.....d................##...............a......#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#.........@............##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#...............a......##......................#
+#......................##......................#
+#......................##.......b..............#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+################################################
+################################################
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#.................d....##...............c......#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#.......c..............##.....b................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+#......................##......................#
+################################################
+`,
+	`name: Dodgeball
+goal: survive 900
+start: right
+map:
+################################################
+#..............................................#
+#..............................................#
+#..............................................#
+#...............|..............................#
+#..............................................#
+#.....O........................................#
+#..............................................#
+#.......................O......................#
+#..............................................#
+#.......................................O......#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#...-..........................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.........@....................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#.................................O............#
+#..............................................#
+#..........................................-...#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#...........O..................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#..............................................#
+#...............................|..............#
+#...........................................H..#
+#..............................................#
+#..............................................#
+################################################
+`,
+}
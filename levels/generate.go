@@ -0,0 +1,277 @@
+package levels
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bcspragu/Snek/rules"
+)
+
+// How much of the board each generator fills in, unless it's asked for
+// something else
+var defaultDensity = map[string]float64{
+	// How many of the walls between maze cells are left standing, beyond the
+	// ones it takes to make a maze at all
+	"maze": 0.7,
+	// How much of the board starts out as rock, before it's smoothed into caves
+	"cave": 0.45,
+	// How much of the board is covered in pillars
+	"pillars": 0.1,
+}
+
+const (
+	// Maze cells are this many cells across, with a wall on their right and
+	// bottom, so there's room to turn around in them
+	mazeCell = 4
+	// How many times the caves are smoothed out
+	caveSteps = 4
+	// How far from the start generators keep clear, so the snek has room to
+	// get going
+	clearance = 3
+)
+
+// Generate lays out a level's walls from a seed, as a "maze", a "cave" or
+// "pillars", so the same seed always gets the same level. Density is between 0
+// and 1, and how much of the board is walled off depends on the kind of level,
+// 0 picks a density that plays well. Every open cell on a generated level can
+// be reached from the start, and the level doesn't have a goal.
+func Generate(kind string, seed int64, density float64, wrap bool) (*Level, error) {
+	if _, ok := defaultDensity[kind]; !ok {
+		return nil, fmt.Errorf("unknown kind of level %q, must be 'maze', 'cave' or 'pillars'", kind)
+	}
+	if density < 0 || density > 1 {
+		return nil, fmt.Errorf("density has to be between 0 and 1, got %g", density)
+	}
+	if density == 0 {
+		density = defaultDensity[kind]
+	}
+
+	l := New(fmt.Sprintf("%s %d", kind, seed))
+	l.Goal = Goal{Kind: Endless}
+	l.Wrap = wrap
+	rng := rand.New(rand.NewSource(seed))
+	switch kind {
+	case "maze":
+		l.maze(rng, density)
+	case "cave":
+		l.cave(rng, density)
+	case "pillars":
+		l.pillars(rng, density)
+	}
+	l.fillUnreachable()
+	l.Dir = l.longestRun()
+	return l, nil
+}
+
+// maze carves a maze out of a board full of walls, which always has a way from
+// every cell to every other. Some of the walls left over are knocked down too,
+// so there are loops to get out of dead ends with.
+func (l *Level) maze(rng *rand.Rand, density float64) {
+	b := l.Board()
+	w, h := b.Width/mazeCell, b.Height/mazeCell
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			if x%mazeCell == mazeCell-1 || y%mazeCell == mazeCell-1 {
+				l.Walls[rules.Loc{X: x, Y: y}] = true
+			}
+		}
+	}
+	// knock knocks down the wall between a maze cell and the one in direction d
+	// from it
+	knock := func(c rules.Loc, d rules.Direction) {
+		for i := 0; i < mazeCell-1; i++ {
+			at := rules.Loc{X: c.X*mazeCell + i, Y: c.Y*mazeCell + mazeCell - 1}
+			if d.X != 0 {
+				at = rules.Loc{X: c.X*mazeCell + mazeCell - 1, Y: c.Y*mazeCell + i}
+			}
+			if d.X < 0 || d.Y < 0 {
+				at.X, at.Y = at.X+d.X*mazeCell, at.Y+d.Y*mazeCell
+			}
+			delete(l.Walls, at)
+		}
+	}
+
+	// Walk the maze depth first, knocking down walls as we go
+	in := func(c rules.Loc) bool { return c.X >= 0 && c.Y >= 0 && c.X < w && c.Y < h }
+	start := rules.Loc{X: l.Start.X / mazeCell, Y: l.Start.Y / mazeCell}
+	seen := map[rules.Loc]bool{start: true}
+	stack := []rules.Loc{start}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		var next []rules.Direction
+		for _, d := range rules.Directions {
+			if n := (rules.Loc{X: c.X + d.X, Y: c.Y + d.Y}); in(n) && !seen[n] {
+				next = append(next, d)
+			}
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		d := next[rng.Intn(len(next))]
+		n := rules.Loc{X: c.X + d.X, Y: c.Y + d.Y}
+		knock(c, d)
+		seen[n] = true
+		stack = append(stack, n)
+	}
+
+	// Knock down some of what's left. Only walls on the right and bottom of each
+	// cell, so we look at each one once.
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			c := rules.Loc{X: x, Y: y}
+			for _, d := range []rules.Direction{rules.Right, rules.Down} {
+				if in(rules.Loc{X: x + d.X, Y: y + d.Y}) && rng.Float64() > density {
+					knock(c, d)
+				}
+			}
+		}
+	}
+	l.Start = rules.Loc{X: start.X*mazeCell + 1, Y: start.Y*mazeCell + 1}
+}
+
+// cave fills the board with rock at random, and smooths it out into caves, the
+// way cellular automata do.
+func (l *Level) cave(rng *rand.Rand, density float64) {
+	b := l.Board()
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			if at := (rules.Loc{X: x, Y: y}); rng.Float64() < density && !l.nearStart(at) {
+				l.Walls[at] = true
+			}
+		}
+	}
+	for i := 0; i < caveSteps; i++ {
+		walls := make(map[rules.Loc]bool)
+		for x := 0; x < b.Width; x++ {
+			for y := 0; y < b.Height; y++ {
+				at := rules.Loc{X: x, Y: y}
+				// Rock stays rock with 4 rocky neighbours, and open cells fill in
+				// with 5
+				n := l.rockAround(b, at)
+				if !l.nearStart(at) && (n >= 5 || l.Walls[at] && n >= 4) {
+					walls[at] = true
+				}
+			}
+		}
+		l.Walls = walls
+	}
+}
+
+// rockAround returns how many of the 8 cells around a cell are walls. Off the
+// edge of a board that doesn't wrap counts as rock, so caves close up against
+// the edges.
+func (l *Level) rockAround(b rules.Board, at rules.Loc) int {
+	n := 0
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			c := rules.Loc{X: at.X + dx, Y: at.Y + dy}
+			if b.Wrap {
+				c.X, c.Y = (c.X+b.Width)%b.Width, (c.Y+b.Height)%b.Height
+			}
+			if !b.InBounds(c) || l.Walls[c] {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// pillars scatters 2x2 pillars around the board, never right up against each
+// other.
+func (l *Level) pillars(rng *rand.Rand, density float64) {
+	b := l.Board()
+	want := int(density * float64(b.Width*b.Height) / 4)
+	// Give up eventually if the board's too full to fit any more
+	for tries := 0; want > 0 && tries < 100*b.Width*b.Height; tries++ {
+		at := rules.Loc{X: rng.Intn(b.Width - 1), Y: rng.Intn(b.Height - 1)}
+		ok := true
+		for dx := -1; dx <= 2 && ok; dx++ {
+			for dy := -1; dy <= 2 && ok; dy++ {
+				c := rules.Loc{X: at.X + dx, Y: at.Y + dy}
+				ok = !l.Walls[c] && !l.nearStart(c)
+			}
+		}
+		if !ok {
+			continue
+		}
+		for dx := 0; dx < 2; dx++ {
+			for dy := 0; dy < 2; dy++ {
+				l.Walls[rules.Loc{X: at.X + dx, Y: at.Y + dy}] = true
+			}
+		}
+		want--
+	}
+}
+
+func (l *Level) nearStart(at rules.Loc) bool {
+	return l.Board().Distance(at, l.Start) <= clearance
+}
+
+// fillUnreachable walls off any open cells the snek can't get to from the
+// start, so food never ends up somewhere it can't reach.
+func (l *Level) fillUnreachable() {
+	b := l.Board()
+	seen := map[rules.Loc]bool{l.Start: true}
+	queue := []rules.Loc{l.Start}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, d := range rules.Directions {
+			if n, ok := b.Next(c, d); ok && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	for x := 0; x < b.Width; x++ {
+		for y := 0; y < b.Height; y++ {
+			if at := (rules.Loc{X: x, Y: y}); !seen[at] {
+				l.Walls[at] = true
+			}
+		}
+	}
+}
+
+// longestRun returns the direction the snek can go the furthest in from the
+// start without turning.
+func (l *Level) longestRun() rules.Direction {
+	b := l.Board()
+	best, most := rules.Right, -1
+	for _, d := range rules.Directions {
+		n := 0
+		for at, ok := b.Next(l.Start, d); ok && n < b.Width; at, ok = b.Next(at, d) {
+			n++
+		}
+		if n > most {
+			best, most = d, n
+		}
+	}
+	return best
+}
+
+// The kinds of level the daily challenge cycles through
+var dailyKinds = []string{"maze", "cave", "pillars"}
+
+// DailyTicks is how many ticks the daily challenge lasts.
+const DailyTicks = 1600
+
+// Daily returns the daily challenge for a day, which is the same for everyone
+// playing it: the kind of arena, its walls, whether it wraps and where the food
+// goes all come from the date.
+func Daily(day time.Time) *Level {
+	y, m, d := day.Date()
+	seed := int64(y*10000 + int(m)*100 + d)
+	kind := dailyKinds[seed%int64(len(dailyKinds))]
+	// Every other time a kind of arena comes round, it wraps. Mazes never do,
+	// since their edges aren't walled off on every side.
+	wrap := kind != "maze" && seed/int64(len(dailyKinds))%2 == 1
+	l, _ := Generate(kind, seed, 0, wrap)
+	l.Name = "Daily challenge " + day.Format("2006-01-02")
+	l.Seed = seed
+	return l
+}
@@ -0,0 +1,74 @@
+package levels
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bcspragu/Snek/rules"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		kind    string
+		density float64
+		wrap    bool
+	}{
+		{kind: "maze"},
+		{kind: "maze", density: 0.2},
+		{kind: "maze", wrap: true},
+		{kind: "cave"},
+		{kind: "cave", density: 0.6},
+		{kind: "cave", wrap: true},
+		{kind: "pillars"},
+		{kind: "pillars", density: 0.3},
+		{kind: "pillars", wrap: true},
+	}
+	for _, test := range tests {
+		for _, seed := range []int64{1, 2, 42} {
+			l, err := Generate(test.kind, seed, test.density, test.wrap)
+			if err != nil {
+				t.Fatalf("Generate(%q, %d, %g, %t): %v", test.kind, seed, test.density, test.wrap, err)
+			}
+			again, err := Generate(test.kind, seed, test.density, test.wrap)
+			if err != nil {
+				t.Fatalf("Generate(%q, %d, %g, %t): %v", test.kind, seed, test.density, test.wrap, err)
+			}
+			if !reflect.DeepEqual(l.Walls, again.Walls) || l.Start != again.Start {
+				t.Errorf("%s %d: generated different levels from the same seed", test.kind, seed)
+			}
+			if len(l.Walls) == 0 {
+				t.Errorf("%s %d: generated a level with no walls", test.kind, seed)
+			}
+
+			b := l.Board()
+			if !b.Open(l.Start) {
+				t.Fatalf("%s %d: the start at %v isn't open", test.kind, seed, l.Start)
+			}
+			reached := reachable(b, l.Start)
+			for x := 0; x < b.Width; x++ {
+				for y := 0; y < b.Height; y++ {
+					if at := (rules.Loc{X: x, Y: y}); b.Open(at) && !reached[at] {
+						t.Errorf("%s %d: %v is open, but can't be reached from the start", test.kind, seed, at)
+					}
+				}
+			}
+		}
+	}
+}
+
+// reachable returns every cell that can be reached from a cell on a board.
+func reachable(b rules.Board, from rules.Loc) map[rules.Loc]bool {
+	seen := map[rules.Loc]bool{from: true}
+	queue := []rules.Loc{from}
+	for len(queue) > 0 {
+		at := queue[0]
+		queue = queue[1:]
+		for _, d := range rules.Directions {
+			if n, ok := b.Next(at, d); ok && !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return seen
+}
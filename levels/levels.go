@@ -0,0 +1,372 @@
+// Package levels reads the levels single player games are played on, and has
+// the campaign's levels built in.
+//
+// A level is a text file of settings, one "key: value" per line, followed by a
+// map of the board:
+//
+//	name: The Box
+//	goal: food 5 in 600
+//	start: up
+//	map:
+//	################
+//	#..............#
+//	#......@.......#
+//
+// Goals are one of "length N", to grow to N long, "food N in T", to eat N food
+// within T ticks, "survive T", to stay alive for T ticks, or "none", to play for
+// as long as you like. The snek starts out on the '@' heading the way "start"
+// says, right if it doesn't, and dies running into a '#'. Each lowercase letter
+// marks a pair of portals, and has to be on the map exactly twice. Hazards
+// start out on an 'O' for a ball, heading down and right, a '-' or '|' for
+// something patrolling across or up and down, and an 'H' for a hunter. If there
+// are any '*'s, food only shows up on them. Anything else on the map is open,
+// and rows and columns past the end of the map are too. Lines starting with ';'
+// are comments.
+package levels
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/bcspragu/Snek/rules"
+)
+
+// GoalKind is what a level wants the player to do.
+type GoalKind int
+
+const (
+	// Grow to a length
+	Length GoalKind = iota
+	// Eat an amount of food within a time limit
+	Food
+	// Stay alive for a number of ticks
+	Survive
+	// Nothing, play for as long as you like
+	Endless
+)
+
+// Goal is what it takes to beat a level.
+type Goal struct {
+	Kind GoalKind
+	// The length to grow to, or how much food to eat
+	N int
+	// The time limit for eating food, or how long to survive
+	Ticks int64
+}
+
+func (g Goal) String() string {
+	switch g.Kind {
+	case Length:
+		return fmt.Sprintf("Grow to a length of %d", g.N)
+	case Food:
+		return fmt.Sprintf("Eat %d food within %d ticks", g.N, g.Ticks)
+	case Survive:
+		return fmt.Sprintf("Survive for %d ticks", g.Ticks)
+	case Endless:
+		return "Play for as long as you like"
+	}
+	return "Unknown goal"
+}
+
+// Level is a board to play on, and what it takes to beat it.
+type Level struct {
+	Name string
+	Goal Goal
+	Wrap bool
+	// Where the snek starts out, and which way it's heading
+	Start   rules.Loc
+	Dir     rules.Direction
+	Walls   map[rules.Loc]bool
+	Portals map[rules.Loc]rules.Loc
+	// Where the hazards start out, which are copied into each game since they
+	// move
+	Hazards []rules.Hazard
+	// The only cells food shows up on, if there are any
+	FoodSpawns []rules.Loc
+	// Seeds where the food goes, so everyone playing the level gets the same
+	// food. It's 0 for different food every game, and isn't saved with the
+	// level.
+	Seed int64
+}
+
+// New returns an empty level, with the snek starting out in the middle heading
+// right.
+func New(name string) *Level {
+	return &Level{
+		Name:    name,
+		Goal:    Goal{Kind: Length, N: 20},
+		Start:   rules.NewBoard(false).Center(),
+		Dir:     rules.Right,
+		Walls:   make(map[rules.Loc]bool),
+		Portals: make(map[rules.Loc]rules.Loc),
+	}
+}
+
+// Board returns the board the level is played on.
+func (l *Level) Board() rules.Board {
+	b := rules.NewBoard(l.Wrap)
+	b.Walls = l.Walls
+	b.Portals = l.Portals
+	b.FoodSpawns = l.FoodSpawns
+	return b
+}
+
+// NewGame starts a game on the level, with the snek and hazards where the level
+// says they start out. Food is placed from the level's seed if it has one, so
+// everyone playing it gets the same game, otherwise from seed.
+func (l *Level) NewGame(seed int64) *rules.Game {
+	if l.Seed != 0 {
+		seed = l.Seed
+	}
+	g := rules.NewGame(l.Board(), rand.New(rand.NewSource(seed)))
+	g.Respawn(l.Start)
+	g.Snek.Dir = l.Dir
+	for _, z := range l.Hazards {
+		z := z
+		g.Hazards = append(g.Hazards, &z)
+	}
+	return g
+}
+
+// Replay plays the level from the start, taking one of the moves every tick
+// until the snek dies or they run out. It returns the game as it was left, and
+// how many of the moves were taken, including the one the snek died on.
+func (l *Level) Replay(moves []rules.Direction) (*rules.Game, int) {
+	g := l.NewGame(0)
+	for i, d := range moves {
+		if _, ok := g.Step(d); !ok {
+			return g, i + 1
+		}
+	}
+	return g, len(moves)
+}
+
+var directions = map[string]rules.Direction{
+	"up":    rules.Up,
+	"down":  rules.Down,
+	"left":  rules.Left,
+	"right": rules.Right,
+}
+
+// Parse reads a level.
+func Parse(r io.Reader) (*Level, error) {
+	b := rules.NewBoard(false)
+	l := New("")
+	portals := make(map[rune][]rules.Loc)
+	haveGoal, inMap := false, false
+	y, n := 0, 0
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		n++
+		line := sc.Text()
+		if inMap {
+			if y >= b.Height {
+				return nil, fmt.Errorf("line %d: the map is taller than the board, which is %d high", n, b.Height)
+			}
+			for x, c := range line {
+				if x >= b.Width {
+					return nil, fmt.Errorf("line %d: the map is wider than the board, which is %d wide", n, b.Width)
+				}
+				switch c {
+				case '#':
+					l.Walls[rules.Loc{X: x, Y: y}] = true
+				case '@':
+					l.Start = rules.Loc{X: x, Y: y}
+				case 'O':
+					l.Hazards = append(l.Hazards, rules.Hazard{Kind: rules.Ball, At: rules.Loc{X: x, Y: y}, Dir: rules.Direction{X: 1, Y: 1}})
+				case '-':
+					l.Hazards = append(l.Hazards, rules.Hazard{Kind: rules.Patrol, At: rules.Loc{X: x, Y: y}, Dir: rules.Right})
+				case '|':
+					l.Hazards = append(l.Hazards, rules.Hazard{Kind: rules.Patrol, At: rules.Loc{X: x, Y: y}, Dir: rules.Down})
+				case 'H':
+					l.Hazards = append(l.Hazards, rules.Hazard{Kind: rules.Hunter, At: rules.Loc{X: x, Y: y}})
+				case '*':
+					l.FoodSpawns = append(l.FoodSpawns, rules.Loc{X: x, Y: y})
+				default:
+					if c >= 'a' && c <= 'z' {
+						portals[c] = append(portals[c], rules.Loc{X: x, Y: y})
+					}
+				}
+			}
+			y++
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected 'key: value', got %q", n, line)
+		}
+		key, val := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "name":
+			l.Name = val
+		case "goal":
+			g, err := parseGoal(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			l.Goal, haveGoal = g, true
+		case "start":
+			d, ok := directions[val]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown direction %q", n, val)
+			}
+			l.Dir = d
+		case "wrap":
+			w, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", n, err)
+			}
+			l.Wrap = w
+		case "map":
+			inMap = true
+		default:
+			return nil, fmt.Errorf("line %d: unknown setting %q", n, key)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	for c, locs := range portals {
+		if len(locs) != 2 {
+			return nil, fmt.Errorf("portal %q is on the map %d times, portals come in pairs", c, len(locs))
+		}
+		l.Portals[locs[0]], l.Portals[locs[1]] = locs[1], locs[0]
+	}
+	if !haveGoal {
+		return nil, fmt.Errorf("level %q doesn't have a goal", l.Name)
+	}
+	if l.Walls[l.Start] {
+		return nil, fmt.Errorf("level %q starts the snek on a wall", l.Name)
+	}
+	if _, ok := l.Portals[l.Start]; ok {
+		return nil, fmt.Errorf("level %q starts the snek on a portal", l.Name)
+	}
+	return l, nil
+}
+
+func parseGoal(s string) (Goal, error) {
+	f := strings.Fields(s)
+	var err error
+	var g Goal
+	switch {
+	case len(f) == 2 && f[0] == "length":
+		g.Kind = Length
+		g.N, err = strconv.Atoi(f[1])
+	case len(f) == 4 && f[0] == "food" && f[2] == "in":
+		g.Kind = Food
+		if g.N, err = strconv.Atoi(f[1]); err == nil {
+			g.Ticks, err = strconv.ParseInt(f[3], 10, 64)
+		}
+	case len(f) == 2 && f[0] == "survive":
+		g.Kind = Survive
+		g.Ticks, err = strconv.ParseInt(f[1], 10, 64)
+	case len(f) == 1 && f[0] == "none":
+		g.Kind = Endless
+	default:
+		return g, fmt.Errorf("goal should be 'length N', 'food N in T', 'survive T' or 'none', got %q", s)
+	}
+	return g, err
+}
+
+// Campaign returns the campaign's levels, in the order they're played.
+func Campaign() ([]*Level, error) {
+	var lvls []*Level
+	for i, c := range campaign {
+		l, err := Parse(strings.NewReader(c))
+		if err != nil {
+			return nil, fmt.Errorf("level %d: %v", i+1, err)
+		}
+		lvls = append(lvls, l)
+	}
+	return lvls, nil
+}
+
+// spec returns the goal the way it's written in a level file.
+func (g Goal) spec() string {
+	switch g.Kind {
+	case Food:
+		return fmt.Sprintf("food %d in %d", g.N, g.Ticks)
+	case Survive:
+		return fmt.Sprintf("survive %d", g.Ticks)
+	case Endless:
+		return "none"
+	}
+	return fmt.Sprintf("length %d", g.N)
+}
+
+// Write writes a level out the way Parse reads it.
+func Write(w io.Writer, l *Level) error {
+	b := rules.NewBoard(false)
+	rows := make([][]byte, b.Height)
+	for y := range rows {
+		rows[y] = []byte(strings.Repeat(".", b.Width))
+	}
+	set := func(at rules.Loc, c byte) {
+		rows[at.Y][at.X] = c
+	}
+	for at := range l.Walls {
+		set(at, '#')
+	}
+	for _, at := range l.FoodSpawns {
+		set(at, '*')
+	}
+	for _, z := range l.Hazards {
+		c := byte('O')
+		switch {
+		case z.Kind == rules.Hunter:
+			c = 'H'
+		case z.Kind == rules.Patrol && z.Dir.X != 0:
+			c = '-'
+		case z.Kind == rules.Patrol:
+			c = '|'
+		}
+		set(z.At, c)
+	}
+	// Letter the portals from the top left, so the same level always comes out
+	// the same
+	next := byte('a')
+	for y := range rows {
+		for x := range rows[y] {
+			at := rules.Loc{X: x, Y: y}
+			exit, ok := l.Portals[at]
+			if !ok || rows[y][x] >= 'a' && rows[y][x] <= 'z' {
+				continue
+			}
+			if next > 'z' {
+				return fmt.Errorf("levels can only have %d pairs of portals", 'z'-'a'+1)
+			}
+			set(at, next)
+			set(exit, next)
+			next++
+		}
+	}
+	set(l.Start, '@')
+
+	dir := "right"
+	for name, d := range directions {
+		if d == l.Dir {
+			dir = name
+		}
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "name: %s\ngoal: %s\nstart: %s\n", l.Name, l.Goal.spec(), dir)
+	if l.Wrap {
+		fmt.Fprintln(bw, "wrap: true")
+	}
+	fmt.Fprintln(bw, "map:")
+	for _, row := range rows {
+		bw.Write(row)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
@@ -1,29 +1,77 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"flag"
 	"fmt"
-	"math/rand"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
+	"github.com/bcspragu/Snek/levels"
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
 	termbox "github.com/nsf/termbox-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
 	Width  = 100
 	Height = 50
+
+	// How often we move when we aren't following a server's clock
+	localTick = 75 * time.Millisecond
 )
 
 var (
-	addr = flag.String("addr", "", "the address of the snek server to connect to")
-	wrap = flag.Bool("wrap", false, "whether or not the snek should wrap around the board")
+	addr   = flag.String("addr", "", "the address of the snek server to connect to")
+	wrap   = flag.Bool("wrap", false, "whether or not the snek should wrap around the board")
+	useTLS = flag.Bool("tls", false, "whether or not to connect to the server over TLS")
+	caFile = flag.String("ca", "", "a CA certificate to verify the server with instead of the system's, implies -tls")
+
+	room     = flag.String("room", "", "the room on the server to play in, defaults to the server's main arena")
+	token    = flag.String("token", "", "a token to authenticate to the server with")
+	user     = flag.String("user", "", "a username to authenticate to the server with, along with -password")
+	password = flag.String("password", os.Getenv("SNEK_PASSWORD"), "the password for -user, defaults to $SNEK_PASSWORD")
+	name     = flag.String("name", "", "the name to play as, on servers that don't need a token or password")
+	team     = flag.Int("team", 0, "the team to play on in team games, 0 to let the server pick")
+
+	edit      = flag.String("edit", "", "open a level file in the level editor instead of playing, creating it if it doesn't exist")
+	portals   = flag.Int("portals", 0, "how many pairs of portals to put on the board, in single player games and generated arenas outside the campaign")
+	arena     = flag.String("arena", "", "play on a generated arena, a 'maze', 'cave' or 'pillars', in single player games")
+	seed      = flag.Int64("seed", 0, "the seed to generate -arena from, so you can play the same one again, defaults to a new one every game")
+	density   = flag.Float64("density", 0, "how much of a generated arena is walls, between 0 and 1, 0 for the generator's default")
+	daily     = flag.Bool("daily", false, "play today's daily challenge, the same game as everyone else today, and send your result to -addr")
+	dailyPath = flag.String("daily_log", "", "the file to keep which daily challenges you've played in, defaults to ~/.snek_daily.json")
+	survival  = flag.Bool("survival", false, "play survival, where more and more hazards show up the longer you last")
+
+	timeAttack = flag.Duration("time_attack", 0, "play time attack for this long, growing as long as you can before time's up, instead of playing until you die")
+	bonusFood  = flag.Bool("bonus_food", false, "whether bonus food that gives you more time shows up in time attack")
+	scoresPath = flag.String("scores", "", "the file to keep time attack high scores in, defaults to ~/.snek_scores.json")
+
+	campaign     = flag.Bool("campaign", false, "play through the campaign's levels, picking up from the first one you haven't beaten")
+	level        = flag.Int("level", 0, "the campaign level to start from, any you've unlocked")
+	progressPath = flag.String("progress", "", "the file to keep how far you've gotten through the campaign in, defaults to ~/.snek_campaign.json")
+
+	tournament  = flag.String("tournament", "", "a tournament on the server to play your next match in")
+	leaderboard = flag.Bool("leaderboard", false, "show the server's highest rated players instead of playing")
+	match       = flag.String("match", "", "find a match on the server for a kind of game, 'classic', 'wrap', 'royale' or 'teams', instead of joining a room")
+	players     = flag.Int("players", 2, "how many players to find a match with, including you")
 
-	keyMap = map[termbox.Key]Direction{
-		termbox.KeyArrowUp:    Up,
-		termbox.KeyArrowDown:  Down,
-		termbox.KeyArrowLeft:  Left,
-		termbox.KeyArrowRight: Right,
+	keyMap = map[termbox.Key]rules.Direction{
+		termbox.KeyArrowUp:    rules.Up,
+		termbox.KeyArrowDown:  rules.Down,
+		termbox.KeyArrowLeft:  rules.Left,
+		termbox.KeyArrowRight: rules.Right,
 	}
 
 	game *Game
@@ -31,7 +79,42 @@ var (
 
 func main() {
 	flag.Parse()
-	rand.Seed(time.Now().UnixNano())
+	if *timeAttack > 0 && *addr != "" {
+		log.Fatal("time attack is a single player mode, it can't be played with -addr")
+	}
+	if *edit != "" && *addr != "" {
+		log.Fatal("the level editor doesn't need a server, it can't be used with -addr")
+	}
+	if *portals > 0 && *addr != "" {
+		log.Fatal("the server's boards don't have portals, -portals can't be played with -addr")
+	}
+	if *campaign && (*addr != "" || *timeAttack > 0 || *portals > 0) {
+		log.Fatal("the campaign is a single player mode on its own levels, it can't be played with -addr, -time_attack or -portals")
+	}
+	if *arena != "" && (*addr != "" || *campaign || *edit != "") {
+		log.Fatal("generated arenas are for single player games, -arena can't be used with -addr, -campaign or -edit")
+	}
+	if *daily && (*campaign || *arena != "" || *edit != "" || *survival || *portals > 0 || *timeAttack > 0 || *bonusFood) {
+		log.Fatal("the daily challenge is the same for everyone, it can't be played with -campaign, -arena, -edit, -survival, -portals, -time_attack or -bonus_food")
+	}
+	if *survival && (*addr != "" || *campaign || *timeAttack > 0) {
+		log.Fatal("survival is a single player mode, it can't be played with -addr, -campaign or -time_attack")
+	}
+	if *tournament != "" {
+		r, err := waitForMatch(*addr, *tournament)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*room = r
+	}
+	if *match != "" {
+		r, err := findMatch(*addr, *match, *players)
+		if err != nil {
+			log.Fatal(err)
+		}
+		*room = r
+	}
+
 	err := termbox.Init()
 	if err != nil {
 		panic(err)
@@ -45,34 +128,233 @@ func main() {
 
 	evChan := make(chan *termbox.Event)
 	go listenToTerm(evChan)
+	if *leaderboard {
+		if err := showLeaderboard(evChan); err != nil {
+			termbox.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+	if *edit != "" {
+		if err := editLevel(evChan, *edit); err != nil {
+			termbox.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+	if *daily {
+		if err := playDaily(evChan); err != nil {
+			termbox.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+	if *campaign {
+		if err := playCampaign(evChan); err != nil {
+			termbox.Close()
+			log.Fatal(err)
+		}
+		return
+	}
+	var lvl *levels.Level
+	if *arena != "" {
+		s := *seed
+		if s == 0 {
+			s = time.Now().Unix()
+		}
+		if lvl, err = levels.Generate(*arena, s, *density, *wrap); err != nil {
+			termbox.Close()
+			log.Fatal(err)
+		}
+	}
 	// Our event loop
-	run(evChan)
+	over := run(evChan, lvl)
+	if *timeAttack > 0 && over {
+		if err := finishTimeAttack(evChan, *timeAttack, game.clock.left <= 0); err != nil {
+			termbox.Close()
+			log.Fatalf("failed to save your score: %v", err)
+		}
+	}
 }
 
-func run(evChan chan *termbox.Event) {
-	game = newGame(*wrap)
+// run plays a game, on a campaign level if there is one, and returns true if it
+// ended by itself instead of the player quitting.
+func run(evChan chan *termbox.Event, lvl *levels.Level) bool {
+	game = newGame(*wrap, lvl)
+	if *timeAttack > 0 {
+		game.clock = newCountdown(*timeAttack)
+	}
+	if *daily {
+		game.clock = newCountdown(dailyTime)
+	}
 
-	if *addr != "" {
-		go game.goOnline(*addr)
+	// When we're online, the server's clock tells us when to move, otherwise we
+	// keep our own time. Levels are always played on our own, even with a server
+	// to send the result to.
+	var t *time.Ticker
+	if *addr != "" && lvl == nil {
+		creds, err := dialCreds()
+		if err != nil {
+			termbox.Close()
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		go game.goOnline(streamContext(), *addr, creds)
+	} else {
+		t = time.NewTicker(localTick)
 	}
 
-	t := time.NewTicker(75 * time.Millisecond)
 	checkTerm()
 	for {
+		var tick <-chan time.Time
+		if t != nil {
+			tick = t.C
+		}
 		select {
 		// Keyboard event
 		case ev := <-evChan:
 			die := handleEvent(ev)
 			if die {
-				return
+				return false
 			}
-		case <-t.C:
+		case resp, ok := <-game.inbox:
+			if !ok {
+				// We lost the server, keep playing on our own
+				game.onlineFunc = nil
+				game.inbox = nil
+				t = time.NewTicker(localTick)
+				continue
+			}
+			if !game.receive(resp) {
+				game.clearSnek()
+				return true
+			}
+		case <-tick:
 			if !game.update() {
 				t.Stop()
 				game.clearSnek()
-				return
+				return true
+			}
+		}
+	}
+}
+
+func dialCreds() (grpc.DialOption, error) {
+	if *caFile != "" {
+		creds, err := credentials.NewClientTLSFromFile(*caFile, "")
+		if err != nil {
+			return nil, err
+		}
+		return grpc.WithTransportCredentials(creds), nil
+	}
+	if *useTLS {
+		return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})), nil
+	}
+	return grpc.WithInsecure(), nil
+}
+
+// streamContext carries which room we want to join and who we are to the
+// server.
+func streamContext() context.Context {
+	var kv []string
+	if *room != "" {
+		kv = append(kv, "room", *room)
+	}
+	if *token != "" {
+		kv = append(kv, "authorization", "Bearer "+*token)
+	}
+	if *user != "" {
+		kv = append(kv, "username", *user, "password", *password)
+	}
+	if *name != "" {
+		kv = append(kv, "name", *name)
+	}
+	if *team != 0 {
+		kv = append(kv, "team", strconv.Itoa(*team))
+	}
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(kv...))
+}
+
+// waitForMatch waits until our next match in a tournament is ready, and
+// returns the room it's in.
+func waitForMatch(addr, name string) (string, error) {
+	if addr == "" {
+		return "", fmt.Errorf("-tournament needs a server to play on, set -addr")
+	}
+	creds, err := dialCreds()
+	if err != nil {
+		return "", err
+	}
+	conn, err := grpc.Dial(addr, creds)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	client := pb.NewSnekClient(conn)
+	waiting := false
+	for {
+		info, err := client.Tournament(streamContext(), &pb.TournamentRequest{Name: name})
+		if err != nil {
+			return "", err
+		}
+		if info.You == "" {
+			return "", fmt.Errorf("the server doesn't know who you are, set -token, -user or -name")
+		}
+		for _, m := range info.Matches {
+			for _, e := range m.Entrants {
+				if e == info.You && !m.Done {
+					return m.Room, nil
+				}
+			}
+		}
+		if info.State == "finished" {
+			return "", fmt.Errorf("tournament %q is over, %s won", name, info.Champion)
+		}
+		if !waiting {
+			fmt.Printf("Waiting for your next match in tournament %q...\n", name)
+			waiting = true
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// findMatch waits in the server's matchmaking queue until we've been matched
+// with other players, and returns the room the match is in.
+func findMatch(addr, mode string, size int) (string, error) {
+	if addr == "" {
+		return "", fmt.Errorf("-match needs a server to play on, set -addr")
+	}
+	creds, err := dialCreds()
+	if err != nil {
+		return "", err
+	}
+	conn, err := grpc.Dial(addr, creds)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	client := pb.NewSnekClient(conn)
+	stream, err := client.FindMatch(streamContext(), &pb.MatchRequest{Mode: mode, Size: int32(size)})
+	if err != nil {
+		return "", err
+	}
+	fmt.Printf("Looking for a %d player %s game...\n", size, mode)
+	waited := false
+	for {
+		u, err := stream.Recv()
+		if err != nil {
+			return "", err
+		}
+		if u.Room != "" {
+			if waited {
+				fmt.Println()
 			}
+			fmt.Printf("Found a match with %s\n", strings.Join(u.Players, ", "))
+			return u.Room, nil
 		}
+		waited = true
+		fmt.Printf("\rWaited %ds, %d waiting, looking within %.0f of your rating ", u.WaitedSeconds, u.Queued, u.Band)
 	}
 }
 
@@ -93,6 +375,41 @@ func handleEvent(ev *termbox.Event) (die bool) {
 	return
 }
 
+// homeFile returns path if it's set, otherwise where the named file goes in the
+// user's home directory.
+func homeFile(path, name string) string {
+	if path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, name)
+}
+
+// loadJSON reads one of the files we keep between games into v, leaving v as it
+// is if there's no file yet.
+func loadJSON(fn string, v interface{}) error {
+	buf, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, v)
+}
+
+// saveJSON writes v out to one of the files we keep between games.
+func saveJSON(fn string, v interface{}) error {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fn, buf, 0644)
+}
+
 func checkTerm() {
 	w, h := termbox.Size()
 	if w < Width || h < Height {
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// portalColors returns the colour each pair of portals is drawn in, so you can
+// tell which ones go together.
+func (g *Game) portalColors() map[rules.Loc]termbox.Attribute {
+	var locs []rules.Loc
+	for l := range g.play.Portals {
+		locs = append(locs, l)
+	}
+	// Number the pairs the same way every time we draw them
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].Y != locs[j].Y {
+			return locs[i].Y < locs[j].Y
+		}
+		return locs[i].X < locs[j].X
+	})
+	colors := make(map[rules.Loc]termbox.Attribute)
+	for _, l := range locs {
+		if _, ok := colors[l]; ok {
+			continue
+		}
+		c := playerColors[len(colors)/2%len(playerColors)]
+		colors[l], colors[g.play.Portals[l]] = c, c
+	}
+	return colors
+}
+
+func (g *Game) drawPortals() {
+	for l, c := range g.portalColors() {
+		if !g.play.Snek.Covers(l) {
+			g.setCell(l, '◊', c)
+		}
+	}
+}
+
+// clearTail clears a cell our snek's tail just left, putting back the portal
+// it was on if there was one.
+func (g *Game) clearTail(l rules.Loc) {
+	if g.play.Portal(l) {
+		g.setCell(l, '◊', g.portalColors()[l])
+		return
+	}
+	g.clearCell(l)
+}
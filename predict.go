@@ -0,0 +1,142 @@
+package main
+
+import (
+	"sort"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+const (
+	// How many of an opponent's moves we'll buffer before we start skipping
+	// ahead to catch up
+	maxOpponentLag = 3
+	// How much weight a new round trip sample gets in our smoothed latency
+	rttWeight = 0.2
+)
+
+type predictedMove struct {
+	head, tail rules.Loc
+	sent       time.Time
+	// What we need to play the move again if an earlier one turns out wrong:
+	// our snek before the move, which way it went, and whether it grew
+	before []rules.Loc
+	dir    rules.Direction
+	grew   bool
+}
+
+// prediction keeps track of the moves we've already drawn locally but that the
+// server hasn't acknowledged yet.
+type prediction struct {
+	pending map[int64]predictedMove
+	rtt     time.Duration
+}
+
+func newPrediction() *prediction {
+	return &prediction{
+		pending: make(map[int64]predictedMove),
+	}
+}
+
+func (p *prediction) add(tick int64, before []rules.Loc, dir rules.Direction, m rules.Move) {
+	p.pending[tick] = predictedMove{
+		head:   m.Head,
+		tail:   m.Tail,
+		sent:   time.Now(),
+		before: before,
+		dir:    dir,
+		grew:   m.Ate,
+	}
+}
+
+// ticks returns the ticks of the moves the server hasn't acknowledged yet, in
+// order.
+func (p *prediction) ticks() []int64 {
+	var ticks []int64
+	for t := range p.pending {
+		ticks = append(ticks, t)
+	}
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i] < ticks[j] })
+	return ticks
+}
+
+// ack reconciles a move the server echoed back to us with the one we
+// predicted. It returns the move we predicted, nil if we don't know anything
+// about the tick, and whether or not they agree.
+func (p *prediction) ack(resp *pb.UpdateResponse) (*predictedMove, bool) {
+	m, ok := p.pending[resp.Tick]
+	if !ok {
+		// We don't know anything about this tick, so we can't trust our state
+		return nil, false
+	}
+	// Anything at or before this tick has either been acked or been lost
+	for t := range p.pending {
+		if t <= resp.Tick {
+			delete(p.pending, t)
+		}
+	}
+
+	sample := time.Since(m.sent)
+	if p.rtt == 0 {
+		p.rtt = sample
+	} else {
+		p.rtt += time.Duration(rttWeight * float64(sample-p.rtt))
+	}
+
+	return &m, m.head == locFromPB(resp.NewHead) && m.tail == locFromPB(resp.OldTail)
+}
+
+// opponent buffers the moves we've received for another snek, so we can draw
+// them at a steady rate no matter how bursty the network is.
+type opponent struct {
+	queue []*pb.UpdateResponse
+	// The tick of the last move we drew for this snek
+	tick int64
+	// How many segments of the snek are on each cell, so we can redraw it
+	cells map[rules.Loc]int
+}
+
+func newOpponent() *opponent {
+	return &opponent{cells: make(map[rules.Loc]int)}
+}
+
+func (o *opponent) push(resp *pb.UpdateResponse) {
+	if resp.Tick <= o.tick {
+		// Stale or duplicate, we've already drawn past this
+		return
+	}
+	o.queue = append(o.queue, resp)
+}
+
+// next returns the moves that should be drawn this tick. Usually that's just
+// one, but if we've fallen behind we draw a few extra to catch up. If we
+// haven't heard from the snek, we return nothing and it holds still.
+func (o *opponent) next() []*pb.UpdateResponse {
+	n := 1
+	if len(o.queue) > maxOpponentLag {
+		n = len(o.queue) - maxOpponentLag + 1
+	}
+	if n > len(o.queue) {
+		n = len(o.queue)
+	}
+	moves := o.queue[:n]
+	o.queue = o.queue[n:]
+	for _, m := range moves {
+		o.tick = m.Tick
+		o.cells[locFromPB(m.NewHead)]++
+		t := locFromPB(m.OldTail)
+		if o.cells[t]--; o.cells[t] <= 0 {
+			delete(o.cells, t)
+		}
+	}
+	return moves
+}
+
+func locFromPB(l *pb.Loc) rules.Loc {
+	return rules.Loc{X: int(l.GetX()), Y: int(l.GetY())}
+}
+
+func locToPB(l rules.Loc) *pb.Loc {
+	return &pb.Loc{X: int32(l.X), Y: int32(l.Y)}
+}
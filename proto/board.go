@@ -0,0 +1,8 @@
+package snek
+
+// Every game is played on a board of the same size, and all locations sent
+// between the clients and the server are relative to its top left corner.
+const (
+	BoardWidth  = 48
+	BoardHeight = 48
+)
@@ -12,6 +12,38 @@ It has these top-level messages:
 	Loc
 	UpdateRequest
 	UpdateResponse
+	Team
+	RoomConfig
+	PlayerInfo
+	RoomInfo
+	ListRoomsRequest
+	ListRoomsResponse
+	KickRequest
+	BanRequest
+	EndGameRequest
+	ConfigureRoomRequest
+	AnnounceRequest
+	AdminResult
+	Entrant
+	TournamentConfig
+	AddEntrantRequest
+	TournamentRequest
+	Standing
+	MatchInfo
+	TournamentInfo
+	MatchRecord
+	PlayerProfile
+	LeaderboardRequest
+	LeaderboardResponse
+	ProfileRequest
+	MatchRequest
+	MatchUpdate
+	DailyStarted
+	DailyResult
+	DailyStanding
+	DailyRequest
+	DailyScore
+	DailyLeaderboardResponse
 */
 package snek
 
@@ -35,6 +67,109 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type PhoneType int32
+
+const (
+	PhoneType_UP    PhoneType = 0
+	PhoneType_DOWN  PhoneType = 1
+	PhoneType_LEFT  PhoneType = 2
+	PhoneType_RIGHT PhoneType = 3
+)
+
+var PhoneType_name = map[int32]string{
+	0: "UP",
+	1: "DOWN",
+	2: "LEFT",
+	3: "RIGHT",
+}
+var PhoneType_value = map[string]int32{
+	"UP":    0,
+	"DOWN":  1,
+	"LEFT":  2,
+	"RIGHT": 3,
+}
+
+func (x PhoneType) String() string {
+	return proto.EnumName(PhoneType_name, int32(x))
+}
+func (PhoneType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type RoomMode int32
+
+const (
+	// Everyone plays for as long as they can stay alive.
+	RoomMode_FREE_FOR_ALL RoomMode = 0
+	// Once there are two sneks in the room the arena starts closing in, until
+	// only one is left. Sneks can't wrap in a battle royale.
+	RoomMode_BATTLE_ROYALE RoomMode = 1
+	// Players are split into teams, and running into a snek on another team
+	// kills you.
+	RoomMode_TEAMS RoomMode = 2
+)
+
+var RoomMode_name = map[int32]string{
+	0: "FREE_FOR_ALL",
+	1: "BATTLE_ROYALE",
+	2: "TEAMS",
+}
+var RoomMode_value = map[string]int32{
+	"FREE_FOR_ALL":  0,
+	"BATTLE_ROYALE": 1,
+	"TEAMS":         2,
+}
+
+func (x RoomMode) String() string {
+	return proto.EnumName(RoomMode_name, int32(x))
+}
+func (RoomMode) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+type TeamScoring int32
+
+const (
+	// A team scores the total length of its living sneks.
+	TeamScoring_LENGTH TeamScoring = 0
+	// A team scores a point for every snek on another team that runs into one of
+	// its sneks.
+	TeamScoring_KILLS TeamScoring = 1
+)
+
+var TeamScoring_name = map[int32]string{
+	0: "LENGTH",
+	1: "KILLS",
+}
+var TeamScoring_value = map[string]int32{
+	"LENGTH": 0,
+	"KILLS":  1,
+}
+
+func (x TeamScoring) String() string {
+	return proto.EnumName(TeamScoring_name, int32(x))
+}
+func (TeamScoring) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type TournamentFormat int32
+
+const (
+	// Everyone plays everyone else once.
+	TournamentFormat_ROUND_ROBIN TournamentFormat = 0
+	// Losers are out, and winners play each other until there's one left.
+	TournamentFormat_SINGLE_ELIMINATION TournamentFormat = 1
+)
+
+var TournamentFormat_name = map[int32]string{
+	0: "ROUND_ROBIN",
+	1: "SINGLE_ELIMINATION",
+}
+var TournamentFormat_value = map[string]int32{
+	"ROUND_ROBIN":        0,
+	"SINGLE_ELIMINATION": 1,
+}
+
+func (x TournamentFormat) String() string {
+	return proto.EnumName(TournamentFormat_name, int32(x))
+}
+func (TournamentFormat) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
 type Loc struct {
 	X int32 `protobuf:"varint,1,opt,name=x" json:"x,omitempty"`
 	Y int32 `protobuf:"varint,2,opt,name=y" json:"y,omitempty"`
@@ -62,6 +197,10 @@ func (m *Loc) GetY() int32 {
 type UpdateRequest struct {
 	NewHead *Loc `protobuf:"bytes,1,opt,name=new_head,json=newHead" json:"new_head,omitempty"`
 	OldTail *Loc `protobuf:"bytes,2,opt,name=old_tail,json=oldTail" json:"old_tail,omitempty"`
+	// The server tick this move was made in response to.
+	Tick int64 `protobuf:"varint,3,opt,name=tick" json:"tick,omitempty"`
+	// Set, instead of a new head and tail, when the snek has died.
+	Dead bool `protobuf:"varint,4,opt,name=dead" json:"dead,omitempty"`
 }
 
 func (m *UpdateRequest) Reset()                    { *m = UpdateRequest{} }
@@ -83,10 +222,46 @@ func (m *UpdateRequest) GetOldTail() *Loc {
 	return nil
 }
 
+func (m *UpdateRequest) GetTick() int64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
+}
+
+func (m *UpdateRequest) GetDead() bool {
+	if m != nil {
+		return m.Dead
+	}
+	return false
+}
+
+// Updates with an id of 0 come from the server's clock, and tell every snek to
+// make its move for that tick.
 type UpdateResponse struct {
 	Id      int32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
 	NewHead *Loc  `protobuf:"bytes,2,opt,name=new_head,json=newHead" json:"new_head,omitempty"`
 	OldTail *Loc  `protobuf:"bytes,3,opt,name=old_tail,json=oldTail" json:"old_tail,omitempty"`
+	// The tick the move was made on, as stamped by the server.
+	Tick int64 `protobuf:"varint,4,opt,name=tick" json:"tick,omitempty"`
+	// Set when the server is echoing back the receiver's own move.
+	Ack bool `protobuf:"varint,5,opt,name=ack" json:"ack,omitempty"`
+	// A message from the server to show to the player.
+	Notice string `protobuf:"bytes,6,opt,name=notice" json:"notice,omitempty"`
+	// Set when the snek has died and should be cleared off the board.
+	Dead bool `protobuf:"varint,7,opt,name=dead" json:"dead,omitempty"`
+	// The rules for the room, sent when a snek joins and whenever they change.
+	Config *RoomConfig `protobuf:"bytes,8,opt,name=config" json:"config,omitempty"`
+	// Sent with every tick from the server's clock, how many cells in from each
+	// edge of the board are out of play. Sneks caught outside die.
+	Margin int32 `protobuf:"varint,9,opt,name=margin" json:"margin,omitempty"`
+	// Sent when a snek joins, its own id.
+	You int32 `protobuf:"varint,10,opt,name=you" json:"you,omitempty"`
+	// Sent when a snek joins a room where it shouldn't start in the middle of the
+	// board, where it should start instead.
+	Spawn *Loc `protobuf:"bytes,11,opt,name=spawn" json:"spawn,omitempty"`
+	// Sent in team games whenever the teams or their scores change.
+	Teams []*Team `protobuf:"bytes,12,rep,name=teams" json:"teams,omitempty"`
 }
 
 func (m *UpdateResponse) Reset()                    { *m = UpdateResponse{} }
@@ -115,132 +290,2020 @@ func (m *UpdateResponse) GetOldTail() *Loc {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Loc)(nil), "snek.Loc")
-	proto.RegisterType((*UpdateRequest)(nil), "snek.UpdateRequest")
-	proto.RegisterType((*UpdateResponse)(nil), "snek.UpdateResponse")
+func (m *UpdateResponse) GetTick() int64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *UpdateResponse) GetAck() bool {
+	if m != nil {
+		return m.Ack
+	}
+	return false
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *UpdateResponse) GetNotice() string {
+	if m != nil {
+		return m.Notice
+	}
+	return ""
+}
 
-// Client API for Snek service
+func (m *UpdateResponse) GetDead() bool {
+	if m != nil {
+		return m.Dead
+	}
+	return false
+}
 
-type SnekClient interface {
-	Update(ctx context.Context, opts ...grpc.CallOption) (Snek_UpdateClient, error)
+func (m *UpdateResponse) GetConfig() *RoomConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
 }
 
-type snekClient struct {
-	cc *grpc.ClientConn
+func (m *UpdateResponse) GetMargin() int32 {
+	if m != nil {
+		return m.Margin
+	}
+	return 0
 }
 
-func NewSnekClient(cc *grpc.ClientConn) SnekClient {
-	return &snekClient{cc}
+func (m *UpdateResponse) GetYou() int32 {
+	if m != nil {
+		return m.You
+	}
+	return 0
 }
 
-func (c *snekClient) Update(ctx context.Context, opts ...grpc.CallOption) (Snek_UpdateClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Snek_serviceDesc.Streams[0], c.cc, "/snek.Snek/Update", opts...)
-	if err != nil {
-		return nil, err
+func (m *UpdateResponse) GetSpawn() *Loc {
+	if m != nil {
+		return m.Spawn
 	}
-	x := &snekUpdateClient{stream}
-	return x, nil
+	return nil
 }
 
-type Snek_UpdateClient interface {
-	Send(*UpdateRequest) error
-	Recv() (*UpdateResponse, error)
-	grpc.ClientStream
+func (m *UpdateResponse) GetTeams() []*Team {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
 }
 
-type snekUpdateClient struct {
-	grpc.ClientStream
+type Team struct {
+	// Teams are numbered from 1.
+	Number int32 `protobuf:"varint,1,opt,name=number" json:"number,omitempty"`
+	// The ids of the sneks on the team.
+	Members []int32 `protobuf:"varint,2,rep,packed,name=members" json:"members,omitempty"`
+	Score   int32   `protobuf:"varint,3,opt,name=score" json:"score,omitempty"`
 }
 
-func (x *snekUpdateClient) Send(m *UpdateRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *Team) Reset()                    { *m = Team{} }
+func (m *Team) String() string            { return proto.CompactTextString(m) }
+func (*Team) ProtoMessage()               {}
+func (*Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *Team) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
 }
 
-func (x *snekUpdateClient) Recv() (*UpdateResponse, error) {
-	m := new(UpdateResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *Team) GetMembers() []int32 {
+	if m != nil {
+		return m.Members
 	}
-	return m, nil
+	return nil
 }
 
-// Server API for Snek service
+func (m *Team) GetScore() int32 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
 
-type SnekServer interface {
-	Update(Snek_UpdateServer) error
+type RoomConfig struct {
+	// How often the room's clock ticks.
+	TickMillis int64 `protobuf:"varint,1,opt,name=tick_millis,json=tickMillis" json:"tick_millis,omitempty"`
+	// Whether sneks wrap around the edges of the board instead of dying.
+	Wrap bool     `protobuf:"varint,2,opt,name=wrap" json:"wrap,omitempty"`
+	Mode RoomMode `protobuf:"varint,3,opt,name=mode,enum=snek.RoomMode" json:"mode,omitempty"`
+	// In a battle royale, how many ticks it takes the arena to close in by a
+	// cell on every side.
+	ShrinkTicks int64 `protobuf:"varint,4,opt,name=shrink_ticks,json=shrinkTicks" json:"shrink_ticks,omitempty"`
+	// In a team game, how many teams there are, 2 if it's 0.
+	Teams int32 `protobuf:"varint,5,opt,name=teams" json:"teams,omitempty"`
+	// In a team game, whether running into a teammate kills you.
+	FriendlyFire bool        `protobuf:"varint,6,opt,name=friendly_fire,json=friendlyFire" json:"friendly_fire,omitempty"`
+	Scoring      TeamScoring `protobuf:"varint,7,opt,name=scoring,enum=snek.TeamScoring" json:"scoring,omitempty"`
+	// How many cells in from each edge of the board are out of play, to make the
+	// arena smaller. A battle royale closes in from here.
+	Margin int32 `protobuf:"varint,8,opt,name=margin" json:"margin,omitempty"`
 }
 
-func RegisterSnekServer(s *grpc.Server, srv SnekServer) {
-	s.RegisterService(&_Snek_serviceDesc, srv)
+func (m *RoomConfig) Reset()                    { *m = RoomConfig{} }
+func (m *RoomConfig) String() string            { return proto.CompactTextString(m) }
+func (*RoomConfig) ProtoMessage()               {}
+func (*RoomConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *RoomConfig) GetTickMillis() int64 {
+	if m != nil {
+		return m.TickMillis
+	}
+	return 0
 }
 
-func _Snek_Update_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(SnekServer).Update(&snekUpdateServer{stream})
+func (m *RoomConfig) GetWrap() bool {
+	if m != nil {
+		return m.Wrap
+	}
+	return false
 }
 
-type Snek_UpdateServer interface {
-	Send(*UpdateResponse) error
-	Recv() (*UpdateRequest, error)
-	grpc.ServerStream
+func (m *RoomConfig) GetMode() RoomMode {
+	if m != nil {
+		return m.Mode
+	}
+	return RoomMode_FREE_FOR_ALL
 }
 
-type snekUpdateServer struct {
-	grpc.ServerStream
+func (m *RoomConfig) GetShrinkTicks() int64 {
+	if m != nil {
+		return m.ShrinkTicks
+	}
+	return 0
 }
 
-func (x *snekUpdateServer) Send(m *UpdateResponse) error {
-	return x.ServerStream.SendMsg(m)
+func (m *RoomConfig) GetTeams() int32 {
+	if m != nil {
+		return m.Teams
+	}
+	return 0
 }
 
-func (x *snekUpdateServer) Recv() (*UpdateRequest, error) {
-	m := new(UpdateRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *RoomConfig) GetFriendlyFire() bool {
+	if m != nil {
+		return m.FriendlyFire
 	}
-	return m, nil
+	return false
 }
 
-var _Snek_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "snek.Snek",
-	HandlerType: (*SnekServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams: []grpc.StreamDesc{
-		{
-			StreamName:    "Update",
-			Handler:       _Snek_Update_Handler,
-			ServerStreams: true,
-			ClientStreams: true,
+func (m *RoomConfig) GetScoring() TeamScoring {
+	if m != nil {
+		return m.Scoring
+	}
+	return TeamScoring_LENGTH
+}
+
+func (m *RoomConfig) GetMargin() int32 {
+	if m != nil {
+		return m.Margin
+	}
+	return 0
+}
+
+type PlayerInfo struct {
+	Id int32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	// Who the player authenticated as, empty if they didn't.
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// The address they're connecting from.
+	Addr string `protobuf:"bytes,3,opt,name=addr" json:"addr,omitempty"`
+	// How many updates are waiting to be sent to them.
+	QueueDepth int32 `protobuf:"varint,4,opt,name=queue_depth,json=queueDepth" json:"queue_depth,omitempty"`
+	// How many illegal moves they've made.
+	Violations int32 `protobuf:"varint,5,opt,name=violations" json:"violations,omitempty"`
+	Dead       bool  `protobuf:"varint,6,opt,name=dead" json:"dead,omitempty"`
+}
+
+func (m *PlayerInfo) Reset()                    { *m = PlayerInfo{} }
+func (m *PlayerInfo) String() string            { return proto.CompactTextString(m) }
+func (*PlayerInfo) ProtoMessage()               {}
+func (*PlayerInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *PlayerInfo) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *PlayerInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PlayerInfo) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *PlayerInfo) GetQueueDepth() int32 {
+	if m != nil {
+		return m.QueueDepth
+	}
+	return 0
+}
+
+func (m *PlayerInfo) GetViolations() int32 {
+	if m != nil {
+		return m.Violations
+	}
+	return 0
+}
+
+func (m *PlayerInfo) GetDead() bool {
+	if m != nil {
+		return m.Dead
+	}
+	return false
+}
+
+type RoomInfo struct {
+	Name    string        `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Tick    int64         `protobuf:"varint,2,opt,name=tick" json:"tick,omitempty"`
+	Config  *RoomConfig   `protobuf:"bytes,3,opt,name=config" json:"config,omitempty"`
+	Players []*PlayerInfo `protobuf:"bytes,4,rep,name=players" json:"players,omitempty"`
+}
+
+func (m *RoomInfo) Reset()                    { *m = RoomInfo{} }
+func (m *RoomInfo) String() string            { return proto.CompactTextString(m) }
+func (*RoomInfo) ProtoMessage()               {}
+func (*RoomInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *RoomInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RoomInfo) GetTick() int64 {
+	if m != nil {
+		return m.Tick
+	}
+	return 0
+}
+
+func (m *RoomInfo) GetConfig() *RoomConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *RoomInfo) GetPlayers() []*PlayerInfo {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type ListRoomsRequest struct {
+}
+
+func (m *ListRoomsRequest) Reset()                    { *m = ListRoomsRequest{} }
+func (m *ListRoomsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRoomsRequest) ProtoMessage()               {}
+func (*ListRoomsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+type ListRoomsResponse struct {
+	Rooms []*RoomInfo `protobuf:"bytes,1,rep,name=rooms" json:"rooms,omitempty"`
+}
+
+func (m *ListRoomsResponse) Reset()                    { *m = ListRoomsResponse{} }
+func (m *ListRoomsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListRoomsResponse) ProtoMessage()               {}
+func (*ListRoomsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *ListRoomsResponse) GetRooms() []*RoomInfo {
+	if m != nil {
+		return m.Rooms
+	}
+	return nil
+}
+
+type KickRequest struct {
+	// The id of the snek to kick.
+	Id int32 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	// Shown to the player.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *KickRequest) Reset()                    { *m = KickRequest{} }
+func (m *KickRequest) String() string            { return proto.CompactTextString(m) }
+func (*KickRequest) ProtoMessage()               {}
+func (*KickRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *KickRequest) GetId() int32 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *KickRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// Bans stop matching players from joining again, and kick any that are
+// already connected. They last until the server restarts.
+type BanRequest struct {
+	// The name the player authenticated as.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// The host the player connects from, without a port.
+	Host string `protobuf:"bytes,2,opt,name=host" json:"host,omitempty"`
+	// Shown to the player.
+	Reason string `protobuf:"bytes,3,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *BanRequest) Reset()                    { *m = BanRequest{} }
+func (m *BanRequest) String() string            { return proto.CompactTextString(m) }
+func (*BanRequest) ProtoMessage()               {}
+func (*BanRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *BanRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BanRequest) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *BanRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type EndGameRequest struct {
+	Room string `protobuf:"bytes,1,opt,name=room" json:"room,omitempty"`
+	// Shown to the players.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *EndGameRequest) Reset()                    { *m = EndGameRequest{} }
+func (m *EndGameRequest) String() string            { return proto.CompactTextString(m) }
+func (*EndGameRequest) ProtoMessage()               {}
+func (*EndGameRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *EndGameRequest) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *EndGameRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type ConfigureRoomRequest struct {
+	Room string `protobuf:"bytes,1,opt,name=room" json:"room,omitempty"`
+	// The new rules for the room.
+	Config *RoomConfig `protobuf:"bytes,2,opt,name=config" json:"config,omitempty"`
+}
+
+func (m *ConfigureRoomRequest) Reset()                    { *m = ConfigureRoomRequest{} }
+func (m *ConfigureRoomRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConfigureRoomRequest) ProtoMessage()               {}
+func (*ConfigureRoomRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *ConfigureRoomRequest) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *ConfigureRoomRequest) GetConfig() *RoomConfig {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type AnnounceRequest struct {
+	// The room to announce to, empty for every room.
+	Room    string `protobuf:"bytes,1,opt,name=room" json:"room,omitempty"`
+	Message string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+}
+
+func (m *AnnounceRequest) Reset()                    { *m = AnnounceRequest{} }
+func (m *AnnounceRequest) String() string            { return proto.CompactTextString(m) }
+func (*AnnounceRequest) ProtoMessage()               {}
+func (*AnnounceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *AnnounceRequest) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *AnnounceRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type AdminResult struct {
+	// How many players were affected.
+	Affected int32 `protobuf:"varint,1,opt,name=affected" json:"affected,omitempty"`
+}
+
+func (m *AdminResult) Reset()                    { *m = AdminResult{} }
+func (m *AdminResult) String() string            { return proto.CompactTextString(m) }
+func (*AdminResult) ProtoMessage()               {}
+func (*AdminResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *AdminResult) GetAffected() int32 {
+	if m != nil {
+		return m.Affected
+	}
+	return 0
+}
+
+type Entrant struct {
+	// The name the player authenticates as, or the bot's name.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Set for Battlesnake bots, which the server plays itself.
+	BotUrl string `protobuf:"bytes,2,opt,name=bot_url,json=botUrl" json:"bot_url,omitempty"`
+}
+
+func (m *Entrant) Reset()                    { *m = Entrant{} }
+func (m *Entrant) String() string            { return proto.CompactTextString(m) }
+func (*Entrant) ProtoMessage()               {}
+func (*Entrant) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *Entrant) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Entrant) GetBotUrl() string {
+	if m != nil {
+		return m.BotUrl
+	}
+	return ""
+}
+
+type TournamentConfig struct {
+	Name   string           `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Format TournamentFormat `protobuf:"varint,2,opt,name=format,enum=snek.TournamentFormat" json:"format,omitempty"`
+	// Entrants in seed order, which is used to break ties.
+	Entrants []*Entrant `protobuf:"bytes,3,rep,name=entrants" json:"entrants,omitempty"`
+	// When the tournament starts, in seconds since the epoch, 0 for right away.
+	StartUnix int64 `protobuf:"varint,4,opt,name=start_unix,json=startUnix" json:"start_unix,omitempty"`
+	// How many ticks a match lasts if both sneks stay alive.
+	MatchTicks int64 `protobuf:"varint,5,opt,name=match_ticks,json=matchTicks" json:"match_ticks,omitempty"`
+}
+
+func (m *TournamentConfig) Reset()                    { *m = TournamentConfig{} }
+func (m *TournamentConfig) String() string            { return proto.CompactTextString(m) }
+func (*TournamentConfig) ProtoMessage()               {}
+func (*TournamentConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *TournamentConfig) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TournamentConfig) GetFormat() TournamentFormat {
+	if m != nil {
+		return m.Format
+	}
+	return TournamentFormat_ROUND_ROBIN
+}
+
+func (m *TournamentConfig) GetEntrants() []*Entrant {
+	if m != nil {
+		return m.Entrants
+	}
+	return nil
+}
+
+func (m *TournamentConfig) GetStartUnix() int64 {
+	if m != nil {
+		return m.StartUnix
+	}
+	return 0
+}
+
+func (m *TournamentConfig) GetMatchTicks() int64 {
+	if m != nil {
+		return m.MatchTicks
+	}
+	return 0
+}
+
+type AddEntrantRequest struct {
+	Tournament string   `protobuf:"bytes,1,opt,name=tournament" json:"tournament,omitempty"`
+	Entrant    *Entrant `protobuf:"bytes,2,opt,name=entrant" json:"entrant,omitempty"`
+}
+
+func (m *AddEntrantRequest) Reset()                    { *m = AddEntrantRequest{} }
+func (m *AddEntrantRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddEntrantRequest) ProtoMessage()               {}
+func (*AddEntrantRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *AddEntrantRequest) GetTournament() string {
+	if m != nil {
+		return m.Tournament
+	}
+	return ""
+}
+
+func (m *AddEntrantRequest) GetEntrant() *Entrant {
+	if m != nil {
+		return m.Entrant
+	}
+	return nil
+}
+
+type TournamentRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *TournamentRequest) Reset()                    { *m = TournamentRequest{} }
+func (m *TournamentRequest) String() string            { return proto.CompactTextString(m) }
+func (*TournamentRequest) ProtoMessage()               {}
+func (*TournamentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *TournamentRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type Standing struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Played int32  `protobuf:"varint,2,opt,name=played" json:"played,omitempty"`
+	Wins   int32  `protobuf:"varint,3,opt,name=wins" json:"wins,omitempty"`
+	Draws  int32  `protobuf:"varint,4,opt,name=draws" json:"draws,omitempty"`
+	Losses int32  `protobuf:"varint,5,opt,name=losses" json:"losses,omitempty"`
+	// A win is worth 1 point, and a draw half of one.
+	Points float64 `protobuf:"fixed64,6,opt,name=points" json:"points,omitempty"`
+	// Set once a player is out of a single elimination tournament.
+	Eliminated bool `protobuf:"varint,7,opt,name=eliminated" json:"eliminated,omitempty"`
+}
+
+func (m *Standing) Reset()                    { *m = Standing{} }
+func (m *Standing) String() string            { return proto.CompactTextString(m) }
+func (*Standing) ProtoMessage()               {}
+func (*Standing) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *Standing) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Standing) GetPlayed() int32 {
+	if m != nil {
+		return m.Played
+	}
+	return 0
+}
+
+func (m *Standing) GetWins() int32 {
+	if m != nil {
+		return m.Wins
+	}
+	return 0
+}
+
+func (m *Standing) GetDraws() int32 {
+	if m != nil {
+		return m.Draws
+	}
+	return 0
+}
+
+func (m *Standing) GetLosses() int32 {
+	if m != nil {
+		return m.Losses
+	}
+	return 0
+}
+
+func (m *Standing) GetPoints() float64 {
+	if m != nil {
+		return m.Points
+	}
+	return 0
+}
+
+func (m *Standing) GetEliminated() bool {
+	if m != nil {
+		return m.Eliminated
+	}
+	return false
+}
+
+type MatchInfo struct {
+	// The room the match is played in.
+	Room     string   `protobuf:"bytes,1,opt,name=room" json:"room,omitempty"`
+	Round    int32    `protobuf:"varint,2,opt,name=round" json:"round,omitempty"`
+	Entrants []string `protobuf:"bytes,3,rep,name=entrants" json:"entrants,omitempty"`
+	// Empty for a draw.
+	Winner string `protobuf:"bytes,4,opt,name=winner" json:"winner,omitempty"`
+	Done   bool   `protobuf:"varint,5,opt,name=done" json:"done,omitempty"`
+}
+
+func (m *MatchInfo) Reset()                    { *m = MatchInfo{} }
+func (m *MatchInfo) String() string            { return proto.CompactTextString(m) }
+func (*MatchInfo) ProtoMessage()               {}
+func (*MatchInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *MatchInfo) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *MatchInfo) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *MatchInfo) GetEntrants() []string {
+	if m != nil {
+		return m.Entrants
+	}
+	return nil
+}
+
+func (m *MatchInfo) GetWinner() string {
+	if m != nil {
+		return m.Winner
+	}
+	return ""
+}
+
+func (m *MatchInfo) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+type TournamentInfo struct {
+	Name   string           `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Format TournamentFormat `protobuf:"varint,2,opt,name=format,enum=snek.TournamentFormat" json:"format,omitempty"`
+	// One of "scheduled", "running" or "finished".
+	State     string `protobuf:"bytes,3,opt,name=state" json:"state,omitempty"`
+	StartUnix int64  `protobuf:"varint,4,opt,name=start_unix,json=startUnix" json:"start_unix,omitempty"`
+	// Best first.
+	Standings []*Standing  `protobuf:"bytes,5,rep,name=standings" json:"standings,omitempty"`
+	Matches   []*MatchInfo `protobuf:"bytes,6,rep,name=matches" json:"matches,omitempty"`
+	Champion  string       `protobuf:"bytes,7,opt,name=champion" json:"champion,omitempty"`
+	// Who the server thinks the caller is, so they can find their matches.
+	You string `protobuf:"bytes,8,opt,name=you" json:"you,omitempty"`
+}
+
+func (m *TournamentInfo) Reset()                    { *m = TournamentInfo{} }
+func (m *TournamentInfo) String() string            { return proto.CompactTextString(m) }
+func (*TournamentInfo) ProtoMessage()               {}
+func (*TournamentInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *TournamentInfo) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TournamentInfo) GetFormat() TournamentFormat {
+	if m != nil {
+		return m.Format
+	}
+	return TournamentFormat_ROUND_ROBIN
+}
+
+func (m *TournamentInfo) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *TournamentInfo) GetStartUnix() int64 {
+	if m != nil {
+		return m.StartUnix
+	}
+	return 0
+}
+
+func (m *TournamentInfo) GetStandings() []*Standing {
+	if m != nil {
+		return m.Standings
+	}
+	return nil
+}
+
+func (m *TournamentInfo) GetMatches() []*MatchInfo {
+	if m != nil {
+		return m.Matches
+	}
+	return nil
+}
+
+func (m *TournamentInfo) GetChampion() string {
+	if m != nil {
+		return m.Champion
+	}
+	return ""
+}
+
+func (m *TournamentInfo) GetYou() string {
+	if m != nil {
+		return m.You
+	}
+	return ""
+}
+
+// A rated result between two players, from one of their points of view.
+type MatchRecord struct {
+	TimeUnix int64  `protobuf:"varint,1,opt,name=time_unix,json=timeUnix" json:"time_unix,omitempty"`
+	Room     string `protobuf:"bytes,2,opt,name=room" json:"room,omitempty"`
+	Opponent string `protobuf:"bytes,3,opt,name=opponent" json:"opponent,omitempty"`
+	// Whether this player outlived their opponent.
+	Won          bool    `protobuf:"varint,4,opt,name=won" json:"won,omitempty"`
+	RatingChange float64 `protobuf:"fixed64,5,opt,name=rating_change,json=ratingChange" json:"rating_change,omitempty"`
+}
+
+func (m *MatchRecord) Reset()                    { *m = MatchRecord{} }
+func (m *MatchRecord) String() string            { return proto.CompactTextString(m) }
+func (*MatchRecord) ProtoMessage()               {}
+func (*MatchRecord) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *MatchRecord) GetTimeUnix() int64 {
+	if m != nil {
+		return m.TimeUnix
+	}
+	return 0
+}
+
+func (m *MatchRecord) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *MatchRecord) GetOpponent() string {
+	if m != nil {
+		return m.Opponent
+	}
+	return ""
+}
+
+func (m *MatchRecord) GetWon() bool {
+	if m != nil {
+		return m.Won
+	}
+	return false
+}
+
+func (m *MatchRecord) GetRatingChange() float64 {
+	if m != nil {
+		return m.RatingChange
+	}
+	return 0
+}
+
+type PlayerProfile struct {
+	Name   string  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Rating float64 `protobuf:"fixed64,2,opt,name=rating" json:"rating,omitempty"`
+	Wins   int32   `protobuf:"varint,3,opt,name=wins" json:"wins,omitempty"`
+	Losses int32   `protobuf:"varint,4,opt,name=losses" json:"losses,omitempty"`
+	// Most recent first.
+	History []*MatchRecord `protobuf:"bytes,5,rep,name=history" json:"history,omitempty"`
+	// Where the player is on the leaderboard, starting from 1.
+	Rank int32 `protobuf:"varint,6,opt,name=rank" json:"rank,omitempty"`
+}
+
+func (m *PlayerProfile) Reset()                    { *m = PlayerProfile{} }
+func (m *PlayerProfile) String() string            { return proto.CompactTextString(m) }
+func (*PlayerProfile) ProtoMessage()               {}
+func (*PlayerProfile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *PlayerProfile) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PlayerProfile) GetRating() float64 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *PlayerProfile) GetWins() int32 {
+	if m != nil {
+		return m.Wins
+	}
+	return 0
+}
+
+func (m *PlayerProfile) GetLosses() int32 {
+	if m != nil {
+		return m.Losses
+	}
+	return 0
+}
+
+func (m *PlayerProfile) GetHistory() []*MatchRecord {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+func (m *PlayerProfile) GetRank() int32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+type LeaderboardRequest struct {
+	// How many players to return, all of them if 0.
+	Limit int32 `protobuf:"varint,1,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *LeaderboardRequest) Reset()                    { *m = LeaderboardRequest{} }
+func (m *LeaderboardRequest) String() string            { return proto.CompactTextString(m) }
+func (*LeaderboardRequest) ProtoMessage()               {}
+func (*LeaderboardRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *LeaderboardRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type LeaderboardResponse struct {
+	Players []*PlayerProfile `protobuf:"bytes,1,rep,name=players" json:"players,omitempty"`
+}
+
+func (m *LeaderboardResponse) Reset()                    { *m = LeaderboardResponse{} }
+func (m *LeaderboardResponse) String() string            { return proto.CompactTextString(m) }
+func (*LeaderboardResponse) ProtoMessage()               {}
+func (*LeaderboardResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *LeaderboardResponse) GetPlayers() []*PlayerProfile {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type ProfileRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *ProfileRequest) Reset()                    { *m = ProfileRequest{} }
+func (m *ProfileRequest) String() string            { return proto.CompactTextString(m) }
+func (*ProfileRequest) ProtoMessage()               {}
+func (*ProfileRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *ProfileRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type MatchRequest struct {
+	// The kind of game to play, "classic", "wrap", "royale" or "teams", classic
+	// if it's empty.
+	Mode string `protobuf:"bytes,1,opt,name=mode" json:"mode,omitempty"`
+	// How many players should be in the game, including the caller, 2 if it's 0.
+	Size int32 `protobuf:"varint,2,opt,name=size" json:"size,omitempty"`
+}
+
+func (m *MatchRequest) Reset()                    { *m = MatchRequest{} }
+func (m *MatchRequest) String() string            { return proto.CompactTextString(m) }
+func (*MatchRequest) ProtoMessage()               {}
+func (*MatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *MatchRequest) GetMode() string {
+	if m != nil {
+		return m.Mode
+	}
+	return ""
+}
+
+func (m *MatchRequest) GetSize() int32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+type MatchUpdate struct {
+	// How long the caller has been waiting, in seconds.
+	WaitedSeconds int64 `protobuf:"varint,1,opt,name=waited_seconds,json=waitedSeconds" json:"waited_seconds,omitempty"`
+	// How far from the caller's rating we're willing to look for opponents.
+	Band float64 `protobuf:"fixed64,2,opt,name=band" json:"band,omitempty"`
+	// How many players are waiting for the same kind of game.
+	Queued int32 `protobuf:"varint,3,opt,name=queued" json:"queued,omitempty"`
+	// Set once a match has been found, the room to play it in.
+	Room string `protobuf:"bytes,4,opt,name=room" json:"room,omitempty"`
+	// Everyone in the match, including the caller and any bots.
+	Players []string `protobuf:"bytes,5,rep,name=players" json:"players,omitempty"`
+}
+
+func (m *MatchUpdate) Reset()                    { *m = MatchUpdate{} }
+func (m *MatchUpdate) String() string            { return proto.CompactTextString(m) }
+func (*MatchUpdate) ProtoMessage()               {}
+func (*MatchUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *MatchUpdate) GetWaitedSeconds() int64 {
+	if m != nil {
+		return m.WaitedSeconds
+	}
+	return 0
+}
+
+func (m *MatchUpdate) GetBand() float64 {
+	if m != nil {
+		return m.Band
+	}
+	return 0
+}
+
+func (m *MatchUpdate) GetQueued() int32 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+func (m *MatchUpdate) GetRoom() string {
+	if m != nil {
+		return m.Room
+	}
+	return ""
+}
+
+func (m *MatchUpdate) GetPlayers() []string {
+	if m != nil {
+		return m.Players
+	}
+	return nil
+}
+
+type DailyStarted struct {
+	// How many ticks the challenge lasts, so how many moves the result can have.
+	Ticks int32 `protobuf:"varint,1,opt,name=ticks" json:"ticks,omitempty"`
+}
+
+func (m *DailyStarted) Reset()                    { *m = DailyStarted{} }
+func (m *DailyStarted) String() string            { return proto.CompactTextString(m) }
+func (*DailyStarted) ProtoMessage()               {}
+func (*DailyStarted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *DailyStarted) GetTicks() int32 {
+	if m != nil {
+		return m.Ticks
+	}
+	return 0
+}
+
+type DailyResult struct {
+	// The day the challenge was for, as YYYY-MM-DD in UTC.
+	Date string `protobuf:"bytes,1,opt,name=date" json:"date,omitempty"`
+	// How long the player's snek got.
+	Length int32 `protobuf:"varint,2,opt,name=length" json:"length,omitempty"`
+	// The way the snek went on every tick, up to and including the one it died
+	// on if it did.
+	Moves []PhoneType `protobuf:"varint,3,rep,packed,name=moves,enum=snek.PhoneType" json:"moves,omitempty"`
+}
+
+func (m *DailyResult) Reset()                    { *m = DailyResult{} }
+func (m *DailyResult) String() string            { return proto.CompactTextString(m) }
+func (*DailyResult) ProtoMessage()               {}
+func (*DailyResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *DailyResult) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyResult) GetLength() int32 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *DailyResult) GetMoves() []PhoneType {
+	if m != nil {
+		return m.Moves
+	}
+	return nil
+}
+
+type DailyStanding struct {
+	// Where the player's result placed, and how many players there are so far.
+	Rank    int32 `protobuf:"varint,1,opt,name=rank" json:"rank,omitempty"`
+	Players int32 `protobuf:"varint,2,opt,name=players" json:"players,omitempty"`
+}
+
+func (m *DailyStanding) Reset()                    { *m = DailyStanding{} }
+func (m *DailyStanding) String() string            { return proto.CompactTextString(m) }
+func (*DailyStanding) ProtoMessage()               {}
+func (*DailyStanding) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *DailyStanding) GetRank() int32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *DailyStanding) GetPlayers() int32 {
+	if m != nil {
+		return m.Players
+	}
+	return 0
+}
+
+type DailyRequest struct {
+	// The day to return results for, as YYYY-MM-DD in UTC, today if it's empty.
+	Date string `protobuf:"bytes,1,opt,name=date" json:"date,omitempty"`
+	// How many results to return, all of them if 0.
+	Limit int32 `protobuf:"varint,2,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *DailyRequest) Reset()                    { *m = DailyRequest{} }
+func (m *DailyRequest) String() string            { return proto.CompactTextString(m) }
+func (*DailyRequest) ProtoMessage()               {}
+func (*DailyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *DailyRequest) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type DailyScore struct {
+	Name   string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Length int32  `protobuf:"varint,2,opt,name=length" json:"length,omitempty"`
+	// When the result came in.
+	TimeUnix int64 `protobuf:"varint,3,opt,name=time_unix,json=timeUnix" json:"time_unix,omitempty"`
+	Rank     int32 `protobuf:"varint,4,opt,name=rank" json:"rank,omitempty"`
+	// When the player started, and whether they've sent their result yet. Only
+	// finished attempts are on the leaderboard.
+	StartedUnix int64 `protobuf:"varint,5,opt,name=started_unix,json=startedUnix" json:"started_unix,omitempty"`
+	Finished    bool  `protobuf:"varint,6,opt,name=finished" json:"finished,omitempty"`
+}
+
+func (m *DailyScore) Reset()                    { *m = DailyScore{} }
+func (m *DailyScore) String() string            { return proto.CompactTextString(m) }
+func (*DailyScore) ProtoMessage()               {}
+func (*DailyScore) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *DailyScore) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DailyScore) GetLength() int32 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *DailyScore) GetTimeUnix() int64 {
+	if m != nil {
+		return m.TimeUnix
+	}
+	return 0
+}
+
+func (m *DailyScore) GetRank() int32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *DailyScore) GetStartedUnix() int64 {
+	if m != nil {
+		return m.StartedUnix
+	}
+	return 0
+}
+
+func (m *DailyScore) GetFinished() bool {
+	if m != nil {
+		return m.Finished
+	}
+	return false
+}
+
+type DailyLeaderboardResponse struct {
+	Date   string        `protobuf:"bytes,1,opt,name=date" json:"date,omitempty"`
+	Scores []*DailyScore `protobuf:"bytes,2,rep,name=scores" json:"scores,omitempty"`
+}
+
+func (m *DailyLeaderboardResponse) Reset()                    { *m = DailyLeaderboardResponse{} }
+func (m *DailyLeaderboardResponse) String() string            { return proto.CompactTextString(m) }
+func (*DailyLeaderboardResponse) ProtoMessage()               {}
+func (*DailyLeaderboardResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *DailyLeaderboardResponse) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyLeaderboardResponse) GetScores() []*DailyScore {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Loc)(nil), "snek.Loc")
+	proto.RegisterType((*UpdateRequest)(nil), "snek.UpdateRequest")
+	proto.RegisterType((*UpdateResponse)(nil), "snek.UpdateResponse")
+	proto.RegisterType((*Team)(nil), "snek.Team")
+	proto.RegisterType((*RoomConfig)(nil), "snek.RoomConfig")
+	proto.RegisterType((*PlayerInfo)(nil), "snek.PlayerInfo")
+	proto.RegisterType((*RoomInfo)(nil), "snek.RoomInfo")
+	proto.RegisterType((*ListRoomsRequest)(nil), "snek.ListRoomsRequest")
+	proto.RegisterType((*ListRoomsResponse)(nil), "snek.ListRoomsResponse")
+	proto.RegisterType((*KickRequest)(nil), "snek.KickRequest")
+	proto.RegisterType((*BanRequest)(nil), "snek.BanRequest")
+	proto.RegisterType((*EndGameRequest)(nil), "snek.EndGameRequest")
+	proto.RegisterType((*ConfigureRoomRequest)(nil), "snek.ConfigureRoomRequest")
+	proto.RegisterType((*AnnounceRequest)(nil), "snek.AnnounceRequest")
+	proto.RegisterType((*AdminResult)(nil), "snek.AdminResult")
+	proto.RegisterType((*Entrant)(nil), "snek.Entrant")
+	proto.RegisterType((*TournamentConfig)(nil), "snek.TournamentConfig")
+	proto.RegisterType((*AddEntrantRequest)(nil), "snek.AddEntrantRequest")
+	proto.RegisterType((*TournamentRequest)(nil), "snek.TournamentRequest")
+	proto.RegisterType((*Standing)(nil), "snek.Standing")
+	proto.RegisterType((*MatchInfo)(nil), "snek.MatchInfo")
+	proto.RegisterType((*TournamentInfo)(nil), "snek.TournamentInfo")
+	proto.RegisterType((*MatchRecord)(nil), "snek.MatchRecord")
+	proto.RegisterType((*PlayerProfile)(nil), "snek.PlayerProfile")
+	proto.RegisterType((*LeaderboardRequest)(nil), "snek.LeaderboardRequest")
+	proto.RegisterType((*LeaderboardResponse)(nil), "snek.LeaderboardResponse")
+	proto.RegisterType((*ProfileRequest)(nil), "snek.ProfileRequest")
+	proto.RegisterType((*MatchRequest)(nil), "snek.MatchRequest")
+	proto.RegisterType((*MatchUpdate)(nil), "snek.MatchUpdate")
+	proto.RegisterType((*DailyStarted)(nil), "snek.DailyStarted")
+	proto.RegisterType((*DailyResult)(nil), "snek.DailyResult")
+	proto.RegisterType((*DailyStanding)(nil), "snek.DailyStanding")
+	proto.RegisterType((*DailyRequest)(nil), "snek.DailyRequest")
+	proto.RegisterType((*DailyScore)(nil), "snek.DailyScore")
+	proto.RegisterType((*DailyLeaderboardResponse)(nil), "snek.DailyLeaderboardResponse")
+	proto.RegisterEnum("snek.PhoneType", PhoneType_name, PhoneType_value)
+	proto.RegisterEnum("snek.RoomMode", RoomMode_name, RoomMode_value)
+	proto.RegisterEnum("snek.TeamScoring", TeamScoring_name, TeamScoring_value)
+	proto.RegisterEnum("snek.TournamentFormat", TournamentFormat_name, TournamentFormat_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Snek service
+
+type SnekClient interface {
+	Update(ctx context.Context, opts ...grpc.CallOption) (Snek_UpdateClient, error)
+	Tournament(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*TournamentInfo, error)
+	Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error)
+	Profile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*PlayerProfile, error)
+	FindMatch(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (Snek_FindMatchClient, error)
+	StartDaily(ctx context.Context, in *DailyRequest, opts ...grpc.CallOption) (*DailyStarted, error)
+	SubmitDaily(ctx context.Context, in *DailyResult, opts ...grpc.CallOption) (*DailyStanding, error)
+	DailyLeaderboard(ctx context.Context, in *DailyRequest, opts ...grpc.CallOption) (*DailyLeaderboardResponse, error)
+}
+
+type snekClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSnekClient(cc *grpc.ClientConn) SnekClient {
+	return &snekClient{cc}
+}
+
+func (c *snekClient) Update(ctx context.Context, opts ...grpc.CallOption) (Snek_UpdateClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Snek_serviceDesc.Streams[0], c.cc, "/snek.Snek/Update", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &snekUpdateClient{stream}
+	return x, nil
+}
+
+type Snek_UpdateClient interface {
+	Send(*UpdateRequest) error
+	Recv() (*UpdateResponse, error)
+	grpc.ClientStream
+}
+
+type snekUpdateClient struct {
+	grpc.ClientStream
+}
+
+func (x *snekUpdateClient) Send(m *UpdateRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *snekUpdateClient) Recv() (*UpdateResponse, error) {
+	m := new(UpdateResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *snekClient) Tournament(ctx context.Context, in *TournamentRequest, opts ...grpc.CallOption) (*TournamentInfo, error) {
+	out := new(TournamentInfo)
+	err := grpc.Invoke(ctx, "/snek.Snek/Tournament", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snekClient) Leaderboard(ctx context.Context, in *LeaderboardRequest, opts ...grpc.CallOption) (*LeaderboardResponse, error) {
+	out := new(LeaderboardResponse)
+	err := grpc.Invoke(ctx, "/snek.Snek/Leaderboard", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snekClient) Profile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*PlayerProfile, error) {
+	out := new(PlayerProfile)
+	err := grpc.Invoke(ctx, "/snek.Snek/Profile", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snekClient) FindMatch(ctx context.Context, in *MatchRequest, opts ...grpc.CallOption) (Snek_FindMatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Snek_serviceDesc.Streams[1], c.cc, "/snek.Snek/FindMatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &snekFindMatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Snek_FindMatchClient interface {
+	Recv() (*MatchUpdate, error)
+	grpc.ClientStream
+}
+
+type snekFindMatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *snekFindMatchClient) Recv() (*MatchUpdate, error) {
+	m := new(MatchUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *snekClient) StartDaily(ctx context.Context, in *DailyRequest, opts ...grpc.CallOption) (*DailyStarted, error) {
+	out := new(DailyStarted)
+	err := grpc.Invoke(ctx, "/snek.Snek/StartDaily", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snekClient) SubmitDaily(ctx context.Context, in *DailyResult, opts ...grpc.CallOption) (*DailyStanding, error) {
+	out := new(DailyStanding)
+	err := grpc.Invoke(ctx, "/snek.Snek/SubmitDaily", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snekClient) DailyLeaderboard(ctx context.Context, in *DailyRequest, opts ...grpc.CallOption) (*DailyLeaderboardResponse, error) {
+	out := new(DailyLeaderboardResponse)
+	err := grpc.Invoke(ctx, "/snek.Snek/DailyLeaderboard", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Snek service
+
+type SnekServer interface {
+	Update(Snek_UpdateServer) error
+	Tournament(context.Context, *TournamentRequest) (*TournamentInfo, error)
+	Leaderboard(context.Context, *LeaderboardRequest) (*LeaderboardResponse, error)
+	Profile(context.Context, *ProfileRequest) (*PlayerProfile, error)
+	FindMatch(*MatchRequest, Snek_FindMatchServer) error
+	StartDaily(context.Context, *DailyRequest) (*DailyStarted, error)
+	SubmitDaily(context.Context, *DailyResult) (*DailyStanding, error)
+	DailyLeaderboard(context.Context, *DailyRequest) (*DailyLeaderboardResponse, error)
+}
+
+func RegisterSnekServer(s *grpc.Server, srv SnekServer) {
+	s.RegisterService(&_Snek_serviceDesc, srv)
+}
+
+func _Snek_Update_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SnekServer).Update(&snekUpdateServer{stream})
+}
+
+type Snek_UpdateServer interface {
+	Send(*UpdateResponse) error
+	Recv() (*UpdateRequest, error)
+	grpc.ServerStream
+}
+
+type snekUpdateServer struct {
+	grpc.ServerStream
+}
+
+func (x *snekUpdateServer) Send(m *UpdateResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *snekUpdateServer) Recv() (*UpdateRequest, error) {
+	m := new(UpdateRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Snek_Tournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TournamentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).Tournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/Tournament",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).Tournament(ctx, req.(*TournamentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snek_Leaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).Leaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/Leaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).Leaderboard(ctx, req.(*LeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snek_Profile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).Profile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/Profile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).Profile(ctx, req.(*ProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snek_FindMatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SnekServer).FindMatch(m, &snekFindMatchServer{stream})
+}
+
+type Snek_FindMatchServer interface {
+	Send(*MatchUpdate) error
+	grpc.ServerStream
+}
+
+type snekFindMatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *snekFindMatchServer) Send(m *MatchUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Snek_StartDaily_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DailyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).StartDaily(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/StartDaily",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).StartDaily(ctx, req.(*DailyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snek_SubmitDaily_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DailyResult)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).SubmitDaily(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/SubmitDaily",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).SubmitDaily(ctx, req.(*DailyResult))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Snek_DailyLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DailyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnekServer).DailyLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Snek/DailyLeaderboard",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnekServer).DailyLeaderboard(ctx, req.(*DailyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Snek_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "snek.Snek",
+	HandlerType: (*SnekServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Tournament",
+			Handler:    _Snek_Tournament_Handler,
+		},
+		{
+			MethodName: "Leaderboard",
+			Handler:    _Snek_Leaderboard_Handler,
+		},
+		{
+			MethodName: "Profile",
+			Handler:    _Snek_Profile_Handler,
+		},
+		{
+			MethodName: "StartDaily",
+			Handler:    _Snek_StartDaily_Handler,
+		},
+		{
+			MethodName: "SubmitDaily",
+			Handler:    _Snek_SubmitDaily_Handler,
+		},
+		{
+			MethodName: "DailyLeaderboard",
+			Handler:    _Snek_DailyLeaderboard_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Update",
+			Handler:       _Snek_Update_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FindMatch",
+			Handler:       _Snek_FindMatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "snek.proto",
+}
+
+// Client API for Admin service
+
+type AdminClient interface {
+	ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error)
+	Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*AdminResult, error)
+	Ban(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*AdminResult, error)
+	EndGame(ctx context.Context, in *EndGameRequest, opts ...grpc.CallOption) (*AdminResult, error)
+	ConfigureRoom(ctx context.Context, in *ConfigureRoomRequest, opts ...grpc.CallOption) (*AdminResult, error)
+	Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AdminResult, error)
+	CreateTournament(ctx context.Context, in *TournamentConfig, opts ...grpc.CallOption) (*AdminResult, error)
+	AddEntrant(ctx context.Context, in *AddEntrantRequest, opts ...grpc.CallOption) (*AdminResult, error)
+}
+
+type adminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAdminClient(cc *grpc.ClientConn) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error) {
+	out := new(ListRoomsResponse)
+	err := grpc.Invoke(ctx, "/snek.Admin/ListRooms", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/Kick", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Ban(ctx context.Context, in *BanRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/Ban", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) EndGame(ctx context.Context, in *EndGameRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/EndGame", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ConfigureRoom(ctx context.Context, in *ConfigureRoomRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/ConfigureRoom", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Announce(ctx context.Context, in *AnnounceRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/Announce", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) CreateTournament(ctx context.Context, in *TournamentConfig, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/CreateTournament", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddEntrant(ctx context.Context, in *AddEntrantRequest, opts ...grpc.CallOption) (*AdminResult, error) {
+	out := new(AdminResult)
+	err := grpc.Invoke(ctx, "/snek.Admin/AddEntrant", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Admin service
+
+type AdminServer interface {
+	ListRooms(context.Context, *ListRoomsRequest) (*ListRoomsResponse, error)
+	Kick(context.Context, *KickRequest) (*AdminResult, error)
+	Ban(context.Context, *BanRequest) (*AdminResult, error)
+	EndGame(context.Context, *EndGameRequest) (*AdminResult, error)
+	ConfigureRoom(context.Context, *ConfigureRoomRequest) (*AdminResult, error)
+	Announce(context.Context, *AnnounceRequest) (*AdminResult, error)
+	CreateTournament(context.Context, *TournamentConfig) (*AdminResult, error)
+	AddEntrant(context.Context, *AddEntrantRequest) (*AdminResult, error)
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/ListRooms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListRooms(ctx, req.(*ListRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/Kick",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Kick(ctx, req.(*KickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Ban_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Ban(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/Ban",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Ban(ctx, req.(*BanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_EndGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).EndGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/EndGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).EndGame(ctx, req.(*EndGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ConfigureRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfigureRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ConfigureRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/ConfigureRoom",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ConfigureRoom(ctx, req.(*ConfigureRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Announce_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnounceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Announce(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/Announce",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Announce(ctx, req.(*AnnounceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_CreateTournament_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TournamentConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).CreateTournament(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/CreateTournament",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).CreateTournament(ctx, req.(*TournamentConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddEntrant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddEntrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddEntrant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snek.Admin/AddEntrant",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddEntrant(ctx, req.(*AddEntrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "snek.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListRooms",
+			Handler:    _Admin_ListRooms_Handler,
+		},
+		{
+			MethodName: "Kick",
+			Handler:    _Admin_Kick_Handler,
+		},
+		{
+			MethodName: "Ban",
+			Handler:    _Admin_Ban_Handler,
+		},
+		{
+			MethodName: "EndGame",
+			Handler:    _Admin_EndGame_Handler,
+		},
+		{
+			MethodName: "ConfigureRoom",
+			Handler:    _Admin_ConfigureRoom_Handler,
+		},
+		{
+			MethodName: "Announce",
+			Handler:    _Admin_Announce_Handler,
+		},
+		{
+			MethodName: "CreateTournament",
+			Handler:    _Admin_CreateTournament_Handler,
+		},
+		{
+			MethodName: "AddEntrant",
+			Handler:    _Admin_AddEntrant_Handler,
 		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "snek.proto",
 }
 
 func init() { proto.RegisterFile("snek.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 209 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0xe2, 0x2a, 0xce, 0x4b, 0xcd,
-	0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01, 0xb1, 0x95, 0x14, 0xb9, 0x98, 0x7d, 0xf2,
-	0x93, 0x85, 0x78, 0xb8, 0x18, 0x2b, 0x24, 0x18, 0x15, 0x18, 0x35, 0x58, 0x83, 0x18, 0x2b, 0x40,
-	0xbc, 0x4a, 0x09, 0x26, 0x08, 0xaf, 0x52, 0x29, 0x9a, 0x8b, 0x37, 0xb4, 0x20, 0x25, 0xb1, 0x24,
-	0x35, 0x28, 0xb5, 0xb0, 0x34, 0xb5, 0xb8, 0x44, 0x48, 0x85, 0x8b, 0x23, 0x2f, 0xb5, 0x3c, 0x3e,
-	0x23, 0x35, 0x31, 0x05, 0xac, 0x87, 0xdb, 0x88, 0x53, 0x0f, 0x6c, 0xb0, 0x4f, 0x7e, 0x72, 0x10,
-	0x7b, 0x5e, 0x6a, 0xb9, 0x47, 0x6a, 0x62, 0x0a, 0x48, 0x55, 0x7e, 0x4e, 0x4a, 0x7c, 0x49, 0x62,
-	0x66, 0x0e, 0xd8, 0x2c, 0x54, 0x55, 0xf9, 0x39, 0x29, 0x21, 0x89, 0x99, 0x39, 0x4a, 0x39, 0x5c,
-	0x7c, 0x30, 0xc3, 0x8b, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x85, 0xf8, 0xb8, 0x98, 0x32, 0x53, 0xa0,
-	0x6e, 0x61, 0xca, 0x4c, 0x41, 0xb1, 0x8d, 0x89, 0x28, 0xdb, 0x98, 0x71, 0xd9, 0x66, 0xe4, 0xc8,
-	0xc5, 0x12, 0x9c, 0x97, 0x9a, 0x2d, 0x64, 0xc9, 0xc5, 0x06, 0xb1, 0x55, 0x48, 0x18, 0xa2, 0x0a,
-	0xc5, 0x83, 0x52, 0x22, 0xa8, 0x82, 0x10, 0x87, 0x29, 0x31, 0x68, 0x30, 0x1a, 0x30, 0x26, 0xb1,
-	0x81, 0x43, 0xcf, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x55, 0xae, 0x35, 0x94, 0x4b, 0x01, 0x00,
-	0x00,
+	// 1970 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x94, 0x58, 0x5f, 0x6f, 0x23, 0x49,
+	0x11, 0xf7, 0xf8, 0xbf, 0xcb, 0x89, 0x77, 0xd2, 0xbb, 0x64, 0x8d, 0x11, 0x7b, 0xb9, 0x61, 0x4f,
+	0x97, 0xcd, 0x1d, 0xab, 0x53, 0x80, 0xc0, 0x1d, 0x1c, 0x47, 0x76, 0xe3, 0xec, 0x46, 0xe7, 0x24,
+	0xab, 0x8e, 0x23, 0x40, 0x42, 0x32, 0x1d, 0x4f, 0x27, 0x6e, 0xc5, 0xee, 0xf6, 0xcd, 0x8c, 0x2f,
+	0x1b, 0x24, 0x9e, 0x78, 0xb9, 0x27, 0x04, 0x8f, 0x7c, 0x83, 0x7b, 0xe4, 0x2b, 0xf0, 0x15, 0x90,
+	0xf8, 0x20, 0x7c, 0x03, 0xd4, 0xd5, 0xdd, 0x33, 0xe3, 0x64, 0x92, 0xe5, 0xde, 0xaa, 0xaa, 0xab,
+	0x6b, 0xaa, 0x7e, 0xf5, 0xa7, 0xbb, 0x07, 0x20, 0x96, 0xfc, 0xf2, 0xf9, 0x3c, 0x52, 0x89, 0x22,
+	0x55, 0x4d, 0x07, 0xef, 0x43, 0x65, 0xa0, 0xc6, 0x64, 0x05, 0xbc, 0xb7, 0x5d, 0x6f, 0xc3, 0xdb,
+	0xac, 0x51, 0xef, 0xad, 0xe6, 0xae, 0xbb, 0x65, 0xc3, 0x5d, 0x07, 0x7f, 0xf1, 0x60, 0xf5, 0x74,
+	0x1e, 0xb2, 0x84, 0x53, 0xfe, 0xd5, 0x82, 0xc7, 0x09, 0x79, 0x0a, 0x4d, 0xc9, 0xaf, 0x46, 0x13,
+	0xce, 0x42, 0xdc, 0xd4, 0xde, 0x6e, 0x3d, 0x47, 0xcb, 0x03, 0x35, 0xa6, 0x0d, 0xc9, 0xaf, 0x5e,
+	0x73, 0x16, 0x6a, 0x2d, 0x35, 0x0d, 0x47, 0x09, 0x13, 0xd3, 0x6e, 0xf9, 0x96, 0x96, 0x9a, 0x86,
+	0x43, 0x26, 0xa6, 0x84, 0x40, 0x35, 0x11, 0xe3, 0xcb, 0x6e, 0x65, 0xc3, 0xdb, 0xac, 0x50, 0xa4,
+	0xb5, 0x2c, 0xd4, 0xb6, 0xab, 0x1b, 0xde, 0x66, 0x93, 0x22, 0x1d, 0xfc, 0xa7, 0x0c, 0x1d, 0xe7,
+	0x45, 0x3c, 0x57, 0x32, 0xe6, 0xa4, 0x03, 0x65, 0x11, 0x5a, 0xaf, 0xcb, 0x22, 0x5c, 0x72, 0xab,
+	0xfc, 0x7f, 0xb9, 0x55, 0x79, 0xa7, 0x5b, 0xd5, 0x9c, 0x5b, 0x3e, 0x54, 0xd8, 0xf8, 0xb2, 0x5b,
+	0x43, 0xaf, 0x34, 0x49, 0xd6, 0xa1, 0x2e, 0x55, 0x22, 0xc6, 0xbc, 0x5b, 0xdf, 0xf0, 0x36, 0x5b,
+	0xd4, 0x72, 0x69, 0x00, 0x8d, 0x2c, 0x00, 0xb2, 0x09, 0xf5, 0xb1, 0x92, 0xe7, 0xe2, 0xa2, 0xdb,
+	0xc4, 0xaf, 0xfa, 0xe6, 0xab, 0x54, 0xa9, 0xd9, 0x4b, 0x94, 0x53, 0xbb, 0xae, 0xad, 0xce, 0x58,
+	0x74, 0x21, 0x64, 0xb7, 0x85, 0xb1, 0x59, 0x4e, 0x7f, 0xff, 0x5a, 0x2d, 0xba, 0x80, 0x42, 0x4d,
+	0x92, 0xf7, 0xa0, 0x16, 0xcf, 0xd9, 0x95, 0xec, 0xb6, 0x6f, 0x06, 0x62, 0xe4, 0x64, 0x03, 0x6a,
+	0x09, 0x67, 0xb3, 0xb8, 0xbb, 0xb2, 0x51, 0xd9, 0x6c, 0x6f, 0x83, 0x51, 0x18, 0x72, 0x36, 0xa3,
+	0x66, 0x21, 0x38, 0x82, 0xaa, 0x66, 0x31, 0x94, 0xc5, 0xec, 0x8c, 0x47, 0x16, 0x50, 0xcb, 0x91,
+	0x2e, 0x34, 0x66, 0x5c, 0x53, 0x71, 0xb7, 0xbc, 0x51, 0xd9, 0xac, 0x51, 0xc7, 0x92, 0x47, 0x50,
+	0x8b, 0xc7, 0x2a, 0xe2, 0x88, 0x62, 0x8d, 0x1a, 0x26, 0xf8, 0xa6, 0x0c, 0x90, 0xc5, 0x44, 0xde,
+	0x83, 0xb6, 0xc6, 0x6e, 0x34, 0x13, 0xd3, 0xa9, 0x88, 0xd1, 0x76, 0x85, 0x82, 0x16, 0x1d, 0xa2,
+	0x44, 0x43, 0x75, 0x15, 0xb1, 0x39, 0x26, 0xac, 0x49, 0x91, 0x26, 0x01, 0x54, 0x67, 0x2a, 0x34,
+	0x86, 0x3b, 0xdb, 0x9d, 0x0c, 0xa8, 0x43, 0x15, 0x72, 0x8a, 0x6b, 0xe4, 0x7d, 0x58, 0x89, 0x27,
+	0x91, 0x90, 0x97, 0x23, 0x6d, 0x2c, 0xb6, 0x89, 0x6a, 0x1b, 0xd9, 0x50, 0x8b, 0xb4, 0x83, 0x26,
+	0xf8, 0x9a, 0x71, 0x10, 0x19, 0xf2, 0x23, 0x58, 0x3d, 0x8f, 0x04, 0x97, 0xe1, 0xf4, 0x7a, 0x74,
+	0x2e, 0x22, 0x93, 0xba, 0x26, 0x5d, 0x71, 0xc2, 0x7d, 0x11, 0x71, 0xf2, 0x11, 0x34, 0x74, 0x38,
+	0x42, 0x5e, 0x60, 0x0e, 0x3b, 0xdb, 0x6b, 0x19, 0x72, 0x27, 0x66, 0x81, 0x3a, 0x8d, 0x5c, 0xbe,
+	0x9a, 0xf9, 0x7c, 0x05, 0xff, 0xf0, 0x00, 0xde, 0x4c, 0xd9, 0x35, 0x8f, 0x0e, 0xe4, 0xb9, 0xba,
+	0x55, 0xae, 0x04, 0xaa, 0x92, 0xcd, 0x38, 0x46, 0xde, 0xa2, 0x48, 0x6b, 0x19, 0x0b, 0xc3, 0x08,
+	0x23, 0x6f, 0x51, 0xa4, 0x35, 0x84, 0x5f, 0x2d, 0xf8, 0x82, 0x8f, 0x42, 0x3e, 0x4f, 0x26, 0x18,
+	0x68, 0x8d, 0x02, 0x8a, 0xf6, 0xb4, 0x84, 0x3c, 0x01, 0xf8, 0x5a, 0xa8, 0x29, 0x4b, 0x84, 0x92,
+	0x2e, 0xd8, 0x9c, 0x24, 0xad, 0xc6, 0x7a, 0xae, 0x9d, 0xbe, 0xf1, 0xa0, 0xa9, 0x11, 0x45, 0xcf,
+	0x9c, 0x27, 0xde, 0xb2, 0x27, 0xd8, 0x00, 0xe5, 0x5c, 0x03, 0x64, 0x25, 0x5c, 0x79, 0x47, 0x09,
+	0x6f, 0x41, 0x63, 0x8e, 0x91, 0xeb, 0xc4, 0x54, 0x32, 0xd5, 0x0c, 0x0e, 0xea, 0x14, 0x02, 0x02,
+	0xfe, 0x40, 0xc4, 0x89, 0xb6, 0x12, 0xdb, 0x09, 0x13, 0x7c, 0x0a, 0x6b, 0x39, 0x99, 0xed, 0xf7,
+	0xa7, 0x50, 0x8b, 0xb4, 0xa0, 0xeb, 0xa1, 0xc9, 0x5c, 0x5d, 0xa0, 0x41, 0xb3, 0x18, 0xfc, 0x0c,
+	0xda, 0x5f, 0x8a, 0xf1, 0xa5, 0x9b, 0x55, 0x37, 0x51, 0x5f, 0x87, 0x7a, 0xc4, 0x59, 0xac, 0xa4,
+	0xc5, 0xdd, 0x72, 0xc1, 0x00, 0xe0, 0x05, 0x93, 0x6e, 0xd7, 0x1d, 0x88, 0x4c, 0x54, 0x9c, 0xb8,
+	0x7c, 0x69, 0x3a, 0x67, 0xad, 0xb2, 0x64, 0xed, 0x57, 0xd0, 0xe9, 0xcb, 0xf0, 0x15, 0x9b, 0xf1,
+	0x9c, 0x45, 0xed, 0x9f, 0xb3, 0xa8, 0xe9, 0x3b, 0x7d, 0x19, 0xc2, 0x23, 0x83, 0xe7, 0x22, 0xe2,
+	0x3a, 0xbc, 0xfb, 0x6c, 0x64, 0x39, 0x29, 0xdf, 0x9f, 0x93, 0xe0, 0x0b, 0x78, 0xb0, 0x2b, 0xa5,
+	0x5a, 0xc8, 0xf1, 0xbd, 0x4e, 0x61, 0xc3, 0xc7, 0x31, 0xbb, 0x70, 0x95, 0xe9, 0xd8, 0xe0, 0x19,
+	0xb4, 0x77, 0xc3, 0x99, 0x90, 0x94, 0xc7, 0x8b, 0x69, 0x42, 0x7a, 0xd0, 0x64, 0xe7, 0xe7, 0x7c,
+	0x9c, 0x70, 0x87, 0x6f, 0xca, 0x07, 0x3b, 0xd0, 0xe8, 0xcb, 0x24, 0x62, 0xb2, 0x18, 0xca, 0xc7,
+	0xd0, 0x38, 0x53, 0xc9, 0x68, 0x11, 0x4d, 0x5d, 0xe4, 0x67, 0x2a, 0x39, 0x8d, 0xa6, 0xc1, 0xbf,
+	0x3c, 0xf0, 0x87, 0x6a, 0x11, 0x69, 0x2d, 0x99, 0xd8, 0x19, 0x52, 0x64, 0xe1, 0x39, 0xd4, 0xcf,
+	0x55, 0x34, 0x63, 0x26, 0x1d, 0x9d, 0xed, 0x75, 0xdb, 0x9f, 0xe9, 0xde, 0x7d, 0x5c, 0xa5, 0x56,
+	0x8b, 0x3c, 0x83, 0x26, 0x37, 0x0e, 0xc5, 0xdd, 0x0a, 0x96, 0xcf, 0xaa, 0xd9, 0x61, 0xdd, 0xa4,
+	0xe9, 0x32, 0xf9, 0x21, 0x40, 0x9c, 0xb0, 0x28, 0x19, 0x2d, 0xa4, 0x78, 0x6b, 0xe7, 0x4a, 0x0b,
+	0x25, 0xa7, 0x52, 0xbc, 0xd5, 0xed, 0x38, 0x63, 0xc9, 0x78, 0x62, 0xe7, 0x4e, 0x0d, 0xd7, 0x01,
+	0x45, 0x38, 0x76, 0x82, 0x3f, 0xc0, 0xda, 0x6e, 0x18, 0x3a, 0xbb, 0x16, 0xe9, 0x27, 0x00, 0x49,
+	0xea, 0x9b, 0x8d, 0x24, 0x27, 0x21, 0x1f, 0x42, 0xc3, 0x3a, 0x60, 0xf3, 0x78, 0xc3, 0x3d, 0xb7,
+	0x1a, 0x7c, 0x08, 0x6b, 0x59, 0x90, 0xf7, 0x94, 0x6b, 0xf0, 0x4f, 0x0f, 0x9a, 0x27, 0x09, 0x93,
+	0xa1, 0x1e, 0x51, 0x45, 0x10, 0xae, 0x43, 0x1d, 0x5b, 0x30, 0xb4, 0x47, 0xbd, 0xe5, 0x70, 0x22,
+	0x0b, 0x19, 0xdb, 0xb1, 0x8e, 0xb4, 0x1e, 0xa5, 0x61, 0xc4, 0xae, 0x62, 0x3b, 0x7d, 0x0c, 0xa3,
+	0x2d, 0x4c, 0x55, 0x1c, 0x73, 0x37, 0x74, 0x2c, 0x87, 0x96, 0x95, 0xd0, 0x50, 0xeb, 0x91, 0xe3,
+	0x51, 0xcb, 0x69, 0x10, 0xf8, 0x54, 0xcc, 0x84, 0x64, 0xba, 0x66, 0xcc, 0xe1, 0x98, 0x93, 0x04,
+	0x7f, 0x86, 0xd6, 0xa1, 0xc6, 0xd1, 0x0d, 0xa5, 0x5b, 0xb5, 0xf9, 0x48, 0x4f, 0x80, 0x85, 0x74,
+	0x1e, 0x1b, 0x46, 0x17, 0xe2, 0x52, 0x6e, 0x5b, 0xb9, 0x64, 0xae, 0x43, 0xfd, 0x4a, 0x48, 0xc9,
+	0x23, 0xf4, 0xbc, 0x45, 0x2d, 0x87, 0x33, 0x51, 0x49, 0x6e, 0x0f, 0x73, 0xa4, 0x83, 0xbf, 0x95,
+	0xa1, 0x93, 0x61, 0x7b, 0xe7, 0x64, 0xfc, 0xae, 0xa5, 0xa7, 0xcf, 0xc9, 0x84, 0x25, 0xdc, 0x8e,
+	0x08, 0xc3, 0xbc, 0xab, 0xca, 0x3e, 0x86, 0x56, 0x6c, 0x93, 0xa7, 0xd1, 0xcd, 0xcd, 0x3b, 0x97,
+	0x53, 0x9a, 0x29, 0x90, 0x67, 0xd0, 0xc0, 0x02, 0xe4, 0x1a, 0x71, 0xad, 0xfb, 0xc0, 0xe8, 0xa6,
+	0x68, 0x52, 0xb7, 0xae, 0xc1, 0x1a, 0x4f, 0xd8, 0x6c, 0x2e, 0x94, 0xc4, 0x0c, 0xb4, 0x68, 0xca,
+	0xbb, 0x0b, 0x46, 0x13, 0xc5, 0x9a, 0x0c, 0xfe, 0xee, 0x41, 0x1b, 0x8d, 0x50, 0x3e, 0x56, 0x51,
+	0x48, 0x7e, 0x00, 0xad, 0x44, 0xcc, 0xb8, 0x71, 0xda, 0x1c, 0xe6, 0x4d, 0x2d, 0x40, 0x9f, 0x5d,
+	0xc6, 0xca, 0xb9, 0x8c, 0xf5, 0xa0, 0xa9, 0xe6, 0x73, 0x25, 0x75, 0xd5, 0x9b, 0xf8, 0x53, 0x5e,
+	0x7f, 0xee, 0x4a, 0x49, 0x7b, 0xcb, 0xd3, 0xa4, 0x3e, 0x9b, 0x23, 0x96, 0x08, 0x79, 0x31, 0x1a,
+	0x4f, 0x98, 0xbc, 0x30, 0xe9, 0xf1, 0xe8, 0x8a, 0x11, 0xbe, 0x44, 0x59, 0xf0, 0xad, 0x07, 0xab,
+	0xe6, 0x1c, 0x79, 0x13, 0xa9, 0x73, 0x31, 0xe5, 0x77, 0x55, 0xb7, 0xd9, 0x85, 0xee, 0x78, 0xd4,
+	0x72, 0x85, 0xd5, 0x9d, 0xd5, 0x71, 0x75, 0xa9, 0x8e, 0x3f, 0x82, 0xc6, 0x44, 0xc4, 0x89, 0x8a,
+	0xae, 0x6d, 0x0a, 0xd6, 0x72, 0xb0, 0x1a, 0x44, 0xa8, 0xd3, 0xc0, 0xe8, 0x99, 0xbc, 0xc4, 0x92,
+	0xaf, 0x51, 0xa4, 0x83, 0x2d, 0x20, 0x03, 0xce, 0x42, 0x1e, 0x9d, 0x29, 0x16, 0x85, 0xae, 0x5b,
+	0x1f, 0x41, 0x4d, 0xd7, 0x7c, 0x62, 0xa7, 0xa6, 0x61, 0x82, 0x3d, 0x78, 0xb8, 0xa4, 0x6b, 0x0f,
+	0xbd, 0x1f, 0x67, 0x27, 0xa9, 0x39, 0xf6, 0x1e, 0xe6, 0x4f, 0x52, 0x8b, 0x40, 0x76, 0x98, 0x3e,
+	0x85, 0x8e, 0x93, 0xdd, 0x33, 0x1b, 0x76, 0x60, 0xc5, 0xc6, 0x90, 0xea, 0xe0, 0x85, 0xcb, 0xea,
+	0x68, 0x5a, 0xcb, 0x62, 0xf1, 0x27, 0x6e, 0x5b, 0x0d, 0xe9, 0xe0, 0xaf, 0xae, 0x1c, 0xcc, 0x4d,
+	0x9c, 0x7c, 0x00, 0x9d, 0x2b, 0x26, 0x12, 0x1e, 0x8e, 0x62, 0x3e, 0x56, 0x32, 0x74, 0x17, 0xbc,
+	0x55, 0x23, 0x3d, 0x31, 0x42, 0x6d, 0xea, 0x8c, 0xd9, 0xae, 0xf5, 0x28, 0xd2, 0x1a, 0x73, 0xbc,
+	0xc2, 0x84, 0x36, 0x13, 0x96, 0x4b, 0x8b, 0xa8, 0xba, 0x7c, 0x24, 0x39, 0x0c, 0x6a, 0xd8, 0xdf,
+	0xb9, 0x70, 0x57, 0xf6, 0x98, 0x98, 0x5e, 0x9f, 0xe8, 0xc6, 0xe1, 0x21, 0x5e, 0xf9, 0x70, 0x2c,
+	0x5b, 0x68, 0x91, 0x09, 0xfe, 0x08, 0x6d, 0xd4, 0xb2, 0x07, 0x97, 0xee, 0x7d, 0xdd, 0x8f, 0x36,
+	0x5a, 0x8c, 0x44, 0x97, 0x00, 0x97, 0x17, 0xc9, 0xc4, 0x0d, 0x43, 0xc3, 0x91, 0x0f, 0xa0, 0x36,
+	0x53, 0x5f, 0x73, 0x33, 0x58, 0x3a, 0xae, 0xaf, 0xde, 0x4c, 0x94, 0xe4, 0xc3, 0xeb, 0x39, 0xa7,
+	0x66, 0x35, 0xf8, 0x1c, 0x56, 0x9d, 0x1f, 0xe9, 0xc0, 0xc5, 0x6a, 0xf0, 0xb2, 0x6a, 0xc8, 0x87,
+	0x61, 0x3e, 0x92, 0x86, 0xf1, 0x0b, 0x1b, 0x46, 0x2e, 0x1f, 0xb7, 0x3c, 0x4c, 0xab, 0xa6, 0x9c,
+	0xaf, 0x9a, 0x6f, 0x3d, 0x00, 0xf3, 0x65, 0x7d, 0xfb, 0xbe, 0xab, 0x13, 0x0a, 0x43, 0x5b, 0xea,
+	0xe5, 0x4a, 0x41, 0x2f, 0x6b, 0xff, 0xab, 0x39, 0xff, 0xf5, 0x95, 0xdb, 0xe0, 0x6c, 0xf6, 0xd4,
+	0xec, 0x95, 0xdb, 0xc8, 0x70, 0x5b, 0x0f, 0x9a, 0xe7, 0x42, 0x8a, 0x78, 0xc2, 0xdd, 0x75, 0x33,
+	0xe5, 0x83, 0xdf, 0x41, 0x17, 0x3d, 0x2d, 0xaa, 0xf2, 0xa2, 0x80, 0x37, 0xa1, 0x8e, 0x4f, 0x0a,
+	0xf3, 0xf0, 0x48, 0x6f, 0x36, 0x59, 0xb4, 0xd4, 0xae, 0x6f, 0x6d, 0x43, 0x2b, 0xcd, 0x08, 0xa9,
+	0x43, 0xf9, 0xf4, 0x8d, 0x5f, 0x22, 0x4d, 0xa8, 0xee, 0x1d, 0xff, 0xf6, 0xc8, 0xf7, 0x34, 0x35,
+	0xe8, 0xef, 0x0f, 0xfd, 0x32, 0x69, 0x41, 0x8d, 0x1e, 0xbc, 0x7a, 0x3d, 0xf4, 0x2b, 0x5b, 0x9f,
+	0x41, 0xd3, 0xbd, 0x28, 0x88, 0x0f, 0x2b, 0xfb, 0xb4, 0xdf, 0x1f, 0xed, 0x1f, 0xd3, 0xd1, 0xee,
+	0x60, 0xe0, 0x97, 0xc8, 0x1a, 0xac, 0xbe, 0xd8, 0x1d, 0x0e, 0x07, 0xfd, 0x11, 0x3d, 0xfe, 0xfd,
+	0xee, 0xa0, 0xef, 0x7b, 0x7a, 0xef, 0xb0, 0xbf, 0x7b, 0x78, 0xe2, 0x97, 0xb7, 0x9e, 0x42, 0x3b,
+	0xf7, 0x10, 0x20, 0x00, 0xf5, 0x41, 0xff, 0xe8, 0xd5, 0xf0, 0xb5, 0x5f, 0xd2, 0x5a, 0x5f, 0x1e,
+	0x0c, 0x06, 0x27, 0xbe, 0xb7, 0xf5, 0x4b, 0xf0, 0x6f, 0x9e, 0x09, 0xe4, 0x01, 0xb4, 0xe9, 0xf1,
+	0xe9, 0xd1, 0xde, 0x88, 0x1e, 0xbf, 0x38, 0x38, 0xf2, 0x4b, 0x64, 0x1d, 0xc8, 0xc9, 0xc1, 0xd1,
+	0xab, 0x41, 0x7f, 0xd4, 0x1f, 0x1c, 0x1c, 0x1e, 0x1c, 0xed, 0x0e, 0x0f, 0x8e, 0x8f, 0x7c, 0x6f,
+	0xfb, 0xbf, 0x15, 0xa8, 0x9e, 0x48, 0x7e, 0x49, 0x3e, 0x85, 0xba, 0x6d, 0x36, 0xdb, 0xf8, 0x4b,
+	0x4f, 0xf1, 0xde, 0xa3, 0x65, 0xa1, 0x81, 0x33, 0x28, 0x6d, 0x7a, 0x9f, 0x78, 0xe4, 0x73, 0x80,
+	0xcc, 0x01, 0xf2, 0xf8, 0xe6, 0x31, 0x75, 0xc3, 0xc4, 0xf2, 0xc9, 0x17, 0x94, 0xc8, 0x1e, 0xb4,
+	0x73, 0xa9, 0x22, 0x5d, 0xfb, 0xb8, 0xbc, 0x35, 0xcf, 0x7a, 0xdf, 0x2f, 0x58, 0x71, 0x8e, 0x90,
+	0x1d, 0x68, 0xb8, 0x31, 0x6d, 0x3f, 0xb4, 0x3c, 0x9f, 0x7a, 0x45, 0xf3, 0x0c, 0xf7, 0xb5, 0xf6,
+	0x85, 0x0c, 0x71, 0xda, 0x10, 0xb2, 0x34, 0x77, 0xcd, 0xbe, 0xfc, 0x2c, 0x36, 0xe1, 0x07, 0xa5,
+	0x4f, 0x3c, 0xb2, 0x03, 0x80, 0xc3, 0x00, 0xcb, 0xc4, 0x6d, 0xcc, 0x37, 0x57, 0x2f, 0x2f, 0xb3,
+	0x73, 0x23, 0x28, 0x91, 0x9f, 0x43, 0xfb, 0x64, 0x71, 0x36, 0x13, 0x76, 0xe3, 0xda, 0xd2, 0x46,
+	0x3d, 0x36, 0x7a, 0x0f, 0x73, 0x22, 0xd7, 0xe7, 0x41, 0x89, 0xbc, 0x06, 0xff, 0x66, 0x59, 0x17,
+	0x7e, 0xf6, 0x49, 0x4e, 0x56, 0x08, 0xd5, 0xf6, 0xbf, 0x2b, 0x50, 0xc3, 0x0b, 0x36, 0xf9, 0x35,
+	0xb4, 0xd2, 0xe7, 0x0f, 0xb1, 0xf7, 0x8b, 0x9b, 0x6f, 0xa4, 0xde, 0xe3, 0x5b, 0xf2, 0x14, 0xf4,
+	0xe7, 0x50, 0xd5, 0x6f, 0x20, 0x17, 0x45, 0xee, 0x3d, 0xe4, 0x60, 0xcb, 0x5d, 0xe4, 0x83, 0x12,
+	0xf9, 0x18, 0x2a, 0x2f, 0x98, 0x24, 0xb6, 0xc3, 0xb2, 0x77, 0x50, 0xb1, 0xf6, 0x4f, 0xa1, 0x61,
+	0x1f, 0x37, 0x2e, 0xa5, 0xcb, 0x6f, 0x9d, 0xe2, 0x5d, 0xbf, 0x81, 0xd5, 0xa5, 0x47, 0x0d, 0xe9,
+	0x19, 0xad, 0xa2, 0x97, 0x4e, 0xb1, 0x85, 0x1d, 0x68, 0xba, 0x07, 0x0c, 0xf9, 0x9e, 0x55, 0x58,
+	0x7e, 0xd0, 0x14, 0xef, 0xfb, 0x02, 0xfc, 0x97, 0x11, 0x67, 0x09, 0xcf, 0x75, 0xc3, 0xad, 0x4b,
+	0x9b, 0x71, 0xa3, 0xd8, 0xc0, 0x67, 0x00, 0xd9, 0x8d, 0xde, 0x35, 0xd2, 0xad, 0x3b, 0x7e, 0xe1,
+	0xde, 0xb3, 0x3a, 0xfe, 0x6d, 0xfb, 0xc9, 0xff, 0x06, 0x00, 0x38, 0x3e, 0xe7, 0x57, 0x7b, 0x13,
+	0x00, 0x00,
 }
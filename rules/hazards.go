@@ -0,0 +1,143 @@
+package rules
+
+// HazardKind is how a hazard moves.
+type HazardKind int
+
+const (
+	// Bounces diagonally off anything it runs into
+	Ball HazardKind = iota
+	// Goes back and forth in a straight line
+	Patrol
+	// Heads for the snek
+	Hunter
+)
+
+func (k HazardKind) String() string {
+	switch k {
+	case Ball:
+		return "ball"
+	case Patrol:
+		return "patrol"
+	case Hunter:
+		return "hunter"
+	}
+	return "hazard"
+}
+
+// How many ticks each kind of hazard takes to move a cell, so sneks can get
+// away from them
+var hazardEvery = map[HazardKind]int64{
+	Ball:   2,
+	Patrol: 2,
+	Hunter: 3,
+}
+
+// How close to a snek's head new hazards can show up
+const spawnDistance = 10
+
+// Hazard is something on the board that moves by itself, and kills the snek if
+// they run into each other. Hazards can't go through walls, sneks or each
+// other.
+type Hazard struct {
+	Kind HazardKind
+	At   Loc
+	// Which way it's heading, diagonally for balls. Hunters pick their own way.
+	Dir Direction
+}
+
+// HazardAt returns whether there's a hazard on the cell.
+func (g *Game) HazardAt(l Loc) bool {
+	for _, z := range g.Hazards {
+		if z.At == l {
+			return true
+		}
+	}
+	return false
+}
+
+// SpawnHazard puts a new hazard somewhere open on the board, far enough from
+// the snek's head that it has a chance to get out of the way. If nowhere is
+// that far it goes as far away as it can, and if nowhere's free at all it
+// doesn't spawn and returns nil.
+func (g *Game) SpawnHazard(kind HazardKind) *Hazard {
+	var far, furthest []Loc
+	dist := -1
+	for _, l := range g.FreeCells() {
+		d := g.Distance(l, g.Snek.Head())
+		if d >= spawnDistance {
+			far = append(far, l)
+		}
+		if d > dist {
+			furthest, dist = nil, d
+		}
+		if d == dist {
+			furthest = append(furthest, l)
+		}
+	}
+	if len(far) == 0 {
+		far = furthest
+	}
+	if len(far) == 0 {
+		return nil
+	}
+	z := &Hazard{Kind: kind, At: far[g.rng.Intn(len(far))]}
+	switch kind {
+	case Ball:
+		z.Dir = Direction{1 - 2*g.rng.Intn(2), 1 - 2*g.rng.Intn(2)}
+	case Patrol:
+		z.Dir = Directions[g.rng.Intn(len(Directions))]
+	}
+	g.Hazards = append(g.Hazards, z)
+	return z
+}
+
+// hazardNext returns the cell a hazard would move onto going in the given
+// direction, and false if it can't go there. Hazards can move onto the snek's
+// head, which kills it, but not the rest of it.
+func (g *Game) hazardNext(l Loc, d Direction) (Loc, bool) {
+	n, ok := g.Next(l, d)
+	if !ok || g.HazardAt(n) {
+		return n, false
+	}
+	return n, n == g.Snek.Head() || !g.Snek.Covers(n)
+}
+
+// moveHazards moves every hazard that's due to move this tick, and returns
+// false if one of them ran into the snek.
+func (g *Game) moveHazards() bool {
+	for _, z := range g.Hazards {
+		if g.Tick%hazardEvery[z.Kind] != 0 {
+			continue
+		}
+		switch z.Kind {
+		case Ball:
+			// Try bouncing off a side, then off a corner
+			d := z.Dir
+			for _, b := range []Direction{d, {-d.X, d.Y}, {d.X, -d.Y}, d.Opposite()} {
+				if n, ok := g.hazardNext(z.At, b); ok {
+					z.At, z.Dir = n, b
+					break
+				}
+			}
+		case Patrol:
+			for _, b := range []Direction{z.Dir, z.Dir.Opposite()} {
+				if n, ok := g.hazardNext(z.At, b); ok {
+					z.At, z.Dir = n, b
+					break
+				}
+			}
+		case Hunter:
+			best, dist := z.At, g.Distance(z.At, g.Snek.Head())
+			for _, b := range Directions {
+				if n, ok := g.hazardNext(z.At, b); ok && g.Distance(n, g.Snek.Head()) < dist {
+					best, dist = n, g.Distance(n, g.Snek.Head())
+				}
+			}
+			z.At = best
+		}
+		if z.At == g.Snek.Head() {
+			return false
+		}
+	}
+	return true
+}
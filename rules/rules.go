@@ -0,0 +1,333 @@
+// Package rules is how a snek moves, grows and dies, without anything to do
+// with drawing it. The terminal client, bots and simulations all play by it.
+package rules
+
+import (
+	"math/rand"
+
+	pb "github.com/bcspragu/Snek/proto"
+)
+
+// StartLength is how long every snek is when it starts out, coiled up on a
+// single cell in the middle of the board.
+const StartLength = 10
+
+// Loc is a cell on the board, with 0, 0 in the top left corner.
+type Loc struct {
+	X, Y int
+}
+
+// Direction is which way a snek is heading.
+type Direction struct {
+	X, Y int
+}
+
+var (
+	Up    = Direction{0, -1}
+	Down  = Direction{0, 1}
+	Left  = Direction{-1, 0}
+	Right = Direction{1, 0}
+
+	// Directions lists every way a snek can go.
+	Directions = []Direction{Up, Down, Left, Right}
+)
+
+// Opposite returns the direction that would take a snek back the way it came.
+func (d Direction) Opposite() Direction {
+	return Direction{-d.X, -d.Y}
+}
+
+// Board is the space the sneks play in.
+type Board struct {
+	Width, Height int
+	// Whether sneks wrap around the edges instead of dying
+	Wrap bool
+	// How many cells in from each edge are out of play, for arenas that close in
+	Margin int
+	// Cells sneks die running into
+	Walls map[Loc]bool
+	// Portals, by the cell they're on, to the cell of the portal they're paired
+	// with. A snek going into one comes out of the other, heading the same way.
+	Portals map[Loc]Loc
+	// The only cells food shows up on, if there are any
+	FoodSpawns []Loc
+}
+
+// NewBoard returns the board every game on the server is played on.
+func NewBoard(wrap bool) Board {
+	return Board{Width: pb.BoardWidth, Height: pb.BoardHeight, Wrap: wrap}
+}
+
+// Center returns the cell in the middle of the board.
+func (b Board) Center() Loc {
+	return Loc{b.Width / 2, b.Height / 2}
+}
+
+func (b Board) InBounds(l Loc) bool {
+	return l.X >= b.Margin && l.Y >= b.Margin && l.X < b.Width-b.Margin && l.Y < b.Height-b.Margin
+}
+
+// Open returns whether a snek can be on the cell, which it can if it's in play
+// and there isn't a wall on it.
+func (b Board) Open(l Loc) bool {
+	return b.InBounds(l) && !b.Walls[l]
+}
+
+// Next returns the cell one step from l in the given direction, coming out the
+// other end of any portal on the way, and false if that's off the board or a
+// wall.
+func (b Board) Next(l Loc, d Direction) (Loc, bool) {
+	n := Loc{l.X + d.X, l.Y + d.Y}
+	if b.Wrap {
+		n.X = (n.X + b.Width) % b.Width
+		n.Y = (n.Y + b.Height) % b.Height
+	}
+	if exit, ok := b.Portals[n]; ok {
+		n = exit
+	}
+	return n, b.Open(n)
+}
+
+// Portal returns whether there's a portal on the cell.
+func (b Board) Portal(l Loc) bool {
+	_, ok := b.Portals[l]
+	return ok
+}
+
+// Distance returns how many moves it takes to get from one cell to another.
+func (b Board) Distance(from, to Loc) int {
+	dx, dy := abs(from.X-to.X), abs(from.Y-to.Y)
+	if b.Wrap {
+		dx, dy = min(dx, b.Width-dx), min(dy, b.Height-dy)
+	}
+	return dx + dy
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Snek is a snek on the board.
+type Snek struct {
+	// The cells the snek covers, with its head at the end. Cells repeat where the
+	// snek is still uncoiling, or has just eaten.
+	Body []Loc
+	Dir  Direction
+}
+
+// NewSnek returns a snek of the given length coiled up on a single cell.
+func NewSnek(at Loc, length int) *Snek {
+	s := &Snek{Dir: Right}
+	for i := 0; i < length; i++ {
+		s.Body = append(s.Body, at)
+	}
+	return s
+}
+
+func (s *Snek) Head() Loc {
+	return s.Body[len(s.Body)-1]
+}
+
+func (s *Snek) Tail() Loc {
+	return s.Body[0]
+}
+
+// Covers returns whether any part of the snek is on the cell.
+func (s *Snek) Covers(l Loc) bool {
+	for _, c := range s.Body {
+		if c == l {
+			return true
+		}
+	}
+	return false
+}
+
+// Within returns whether every part of the snek is in play on the board.
+func (s *Snek) Within(b Board) bool {
+	for _, c := range s.Body {
+		if !b.InBounds(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Move is what changed when a snek took a step.
+type Move struct {
+	// The cell the snek moved onto
+	Head Loc
+	// The cell its tail moved off of, which is still covered if the snek just
+	// ate, or is still uncoiling
+	Tail Loc
+	// Whether it ate its food
+	Ate bool
+}
+
+// Game is a single snek's game: the snek, and the food only it can eat.
+type Game struct {
+	Board
+	Snek *Snek
+	Food Loc
+	// How many times the snek has moved
+	Tick int64
+	// Things moving around the board that the snek has to stay away from
+	Hazards []*Hazard
+	rng     *rand.Rand
+}
+
+// NewGame starts a snek in the middle of the board, with food placed by rng.
+func NewGame(b Board, rng *rand.Rand) *Game {
+	g := &Game{
+		Board: b,
+		Snek:  NewSnek(b.Center(), StartLength),
+		rng:   rng,
+	}
+	g.NewFood()
+	return g
+}
+
+// Respawn starts the snek over, coiled up on the given cell.
+func (g *Game) Respawn(at Loc) {
+	g.Snek = NewSnek(at, StartLength)
+	if g.Snek.Covers(g.Food) {
+		g.NewFood()
+	}
+}
+
+// NewFood puts the snek's food somewhere it isn't. Food never goes on a
+// portal, since nothing can stop on one. If the board has spawns for food, it
+// goes on one of those, unless they're all taken.
+func (g *Game) NewFood() {
+	var free []Loc
+	for _, l := range g.FoodSpawns {
+		if g.foodCanGo(l) {
+			free = append(free, l)
+		}
+	}
+	if len(free) > 0 {
+		g.Food = free[g.rng.Intn(len(free))]
+		return
+	}
+	// Pick a cell, and look along from there for one the food can go on. Every
+	// food takes the same draws from rng wherever the snek is, so games with the
+	// same seed get their food in the same places.
+	start := g.rng.Intn(g.Width * g.Height)
+	for i := 0; i < g.Width*g.Height; i++ {
+		n := (start + i) % (g.Width * g.Height)
+		g.Food = Loc{n % g.Width, n / g.Width}
+		if g.foodCanGo(g.Food) {
+			return
+		}
+	}
+}
+
+func (g *Game) foodCanGo(l Loc) bool {
+	return g.Open(l) && !g.Portal(l) && !g.Snek.Covers(l) && !g.HazardAt(l)
+}
+
+// AddPortals puts n pairs of portals on open cells of the board that the snek
+// and its food aren't on, or as many as will fit.
+func (g *Game) AddPortals(n int) {
+	if g.Portals == nil {
+		g.Portals = make(map[Loc]Loc)
+	}
+	free := g.FreeCells()
+	take := func() Loc {
+		i := g.rng.Intn(len(free))
+		l := free[i]
+		free[i] = free[len(free)-1]
+		free = free[:len(free)-1]
+		return l
+	}
+	for i := 0; i < n && len(free) >= 2; i++ {
+		a, b := take(), take()
+		g.Portals[a], g.Portals[b] = b, a
+	}
+}
+
+// FreeCells returns every open cell with nothing on it, in order.
+func (g *Game) FreeCells() []Loc {
+	var free []Loc
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			l := Loc{x, y}
+			if g.Open(l) && !g.Portal(l) && l != g.Food && !g.Snek.Covers(l) && !g.HazardAt(l) {
+				free = append(free, l)
+			}
+		}
+	}
+	return free
+}
+
+// CloseIn moves the edges of the arena in to margin cells from the edges of the
+// board, moving the food if it was left outside. It returns false if the snek
+// was caught outside, which kills it.
+func (g *Game) CloseIn(margin int) bool {
+	g.Margin = margin
+	if !g.Open(g.Food) {
+		g.NewFood()
+	}
+	return g.Snek.Within(g.Board)
+}
+
+// Step moves the snek one cell in the given direction, then any hazards, and
+// returns false if the snek died doing it. Sneks can't turn back on themselves,
+// so they keep going the way they were if asked to.
+func (g *Game) Step(d Direction) (Move, bool) {
+	s := g.Snek
+	if d != s.Dir.Opposite() {
+		s.Dir = d
+	}
+	g.Tick++
+
+	h, ok := g.Next(s.Head(), s.Dir)
+	if !ok || s.Covers(h) || g.HazardAt(h) {
+		return Move{Head: h}, false
+	}
+	m := Move{Head: h, Ate: h == g.Food}
+	s.Body = append(s.Body, h)
+	if m.Ate {
+		// Grow by leaving an extra copy of our tail behind
+		s.Body = append(s.Body[0:1], s.Body...)
+		g.NewFood()
+	}
+	m.Tail = s.Body[0]
+	s.Body = s.Body[1:]
+	return m, g.moveHazards()
+}
+
+// Follow moves a snek we only know about from the moves it sends, like someone
+// else's on the server. It starts out with an empty body, and since sneks start
+// out coiled up, we only drop a tail once we've seen the snek move onto it.
+func (s *Snek) Follow(head, tail Loc) {
+	if len(s.Body) > 0 {
+		prev := s.Head()
+		s.Dir = Direction{unwrap(head.X - prev.X), unwrap(head.Y - prev.Y)}
+	}
+	s.Body = append(s.Body, head)
+	if len(s.Body) > 1 && s.Body[0] == tail {
+		s.Body = s.Body[1:]
+	}
+}
+
+// unwrap turns a jump from one edge of the board to the other into a single
+// step.
+func unwrap(d int) int {
+	switch {
+	case d > 1:
+		return -1
+	case d < -1:
+		return 1
+	}
+	return d
+}
@@ -0,0 +1,224 @@
+package rules
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		desc    string
+		wrap    bool
+		margin  int
+		walls   map[Loc]bool
+		portals map[Loc]Loc
+		from    Loc
+		dir     Direction
+		want    Loc
+		wantOK  bool
+	}{
+		{desc: "open", from: Loc{5, 5}, dir: Right, want: Loc{6, 5}, wantOK: true},
+		{desc: "off the edge", from: Loc{9, 5}, dir: Right, want: Loc{10, 5}},
+		{desc: "off the top", from: Loc{5, 0}, dir: Up, want: Loc{5, -1}},
+		{desc: "wrap right", wrap: true, from: Loc{9, 5}, dir: Right, want: Loc{0, 5}, wantOK: true},
+		{desc: "wrap up", wrap: true, from: Loc{3, 0}, dir: Up, want: Loc{3, 9}, wantOK: true},
+		{desc: "wrap into margin", wrap: true, margin: 1, from: Loc{9, 5}, dir: Right, want: Loc{0, 5}},
+		{desc: "margin", margin: 1, from: Loc{8, 5}, dir: Right, want: Loc{9, 5}},
+		{desc: "wall", walls: map[Loc]bool{{6, 5}: true}, from: Loc{5, 5}, dir: Right, want: Loc{6, 5}},
+		{
+			desc:    "portal",
+			portals: map[Loc]Loc{{6, 5}: {2, 2}, {2, 2}: {6, 5}},
+			from:    Loc{5, 5}, dir: Right, want: Loc{2, 2}, wantOK: true,
+		},
+		{
+			desc:    "portal after wrapping",
+			wrap:    true,
+			portals: map[Loc]Loc{{0, 5}: {3, 3}, {3, 3}: {0, 5}},
+			from:    Loc{9, 5}, dir: Right, want: Loc{3, 3}, wantOK: true,
+		},
+		{
+			desc:    "portal onto a wall",
+			walls:   map[Loc]bool{{2, 2}: true},
+			portals: map[Loc]Loc{{6, 5}: {2, 2}, {2, 2}: {6, 5}},
+			from:    Loc{5, 5}, dir: Right, want: Loc{2, 2},
+		},
+	}
+	for _, test := range tests {
+		b := Board{Width: 10, Height: 10, Wrap: test.wrap, Margin: test.margin, Walls: test.walls, Portals: test.portals}
+		got, ok := b.Next(test.from, test.dir)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%s: Next(%v, %v) = %v, %t, want %v, %t", test.desc, test.from, test.dir, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestStep(t *testing.T) {
+	tests := []struct {
+		desc    string
+		body    []Loc
+		food    Loc
+		walls   map[Loc]bool
+		hazards []*Hazard
+		dir     Direction
+		want    Move
+		wantOK  bool
+		// The snek's body and direction after the step
+		wantBody []Loc
+		wantDir  Direction
+	}{
+		{
+			desc: "straight on",
+			body: []Loc{{4, 5}, {5, 5}}, dir: Right,
+			want: Move{Head: Loc{6, 5}, Tail: Loc{4, 5}}, wantOK: true,
+			wantBody: []Loc{{5, 5}, {6, 5}}, wantDir: Right,
+		},
+		{
+			desc: "turn",
+			body: []Loc{{4, 5}, {5, 5}}, dir: Down,
+			want: Move{Head: Loc{5, 6}, Tail: Loc{4, 5}}, wantOK: true,
+			wantBody: []Loc{{5, 5}, {5, 6}}, wantDir: Down,
+		},
+		{
+			desc: "can't turn back",
+			body: []Loc{{4, 5}, {5, 5}}, dir: Left,
+			want: Move{Head: Loc{6, 5}, Tail: Loc{4, 5}}, wantOK: true,
+			wantBody: []Loc{{5, 5}, {6, 5}}, wantDir: Right,
+		},
+		{
+			desc: "eat",
+			body: []Loc{{4, 5}, {5, 5}}, food: Loc{6, 5}, dir: Right,
+			want: Move{Head: Loc{6, 5}, Tail: Loc{4, 5}, Ate: true}, wantOK: true,
+			wantBody: []Loc{{4, 5}, {5, 5}, {6, 5}}, wantDir: Right,
+		},
+		{
+			desc: "uncoil",
+			body: []Loc{{5, 5}, {5, 5}, {5, 5}}, dir: Right,
+			want: Move{Head: Loc{6, 5}, Tail: Loc{5, 5}}, wantOK: true,
+			wantBody: []Loc{{5, 5}, {5, 5}, {6, 5}}, wantDir: Right,
+		},
+		{
+			desc: "wall",
+			body: []Loc{{4, 5}, {5, 5}}, walls: map[Loc]bool{{6, 5}: true}, dir: Right,
+			want: Move{Head: Loc{6, 5}}, wantBody: []Loc{{4, 5}, {5, 5}}, wantDir: Right,
+		},
+		{
+			desc: "edge",
+			body: []Loc{{8, 5}, {9, 5}}, dir: Right,
+			want: Move{Head: Loc{10, 5}}, wantBody: []Loc{{8, 5}, {9, 5}}, wantDir: Right,
+		},
+		{
+			desc: "itself",
+			body: []Loc{{5, 4}, {6, 4}, {6, 5}, {5, 5}}, dir: Up,
+			want: Move{Head: Loc{5, 4}}, wantBody: []Loc{{5, 4}, {6, 4}, {6, 5}, {5, 5}}, wantDir: Up,
+		},
+		{
+			desc: "hazard",
+			body: []Loc{{4, 5}, {5, 5}}, hazards: []*Hazard{{Kind: Patrol, At: Loc{6, 5}, Dir: Up}}, dir: Right,
+			want: Move{Head: Loc{6, 5}}, wantBody: []Loc{{4, 5}, {5, 5}}, wantDir: Right,
+		},
+	}
+	for _, test := range tests {
+		g := &Game{
+			Board:   Board{Width: 10, Height: 10, Walls: test.walls},
+			Snek:    &Snek{Body: append([]Loc(nil), test.body...), Dir: Right},
+			Food:    test.food,
+			Hazards: test.hazards,
+			rng:     rand.New(rand.NewSource(1)),
+		}
+		if test.food == (Loc{}) {
+			g.Food = Loc{0, 9}
+		}
+		got, ok := g.Step(test.dir)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%s: Step(%v) = %+v, %t, want %+v, %t", test.desc, test.dir, got, ok, test.want, test.wantOK)
+		}
+		if !reflect.DeepEqual(g.Snek.Body, test.wantBody) || g.Snek.Dir != test.wantDir {
+			t.Errorf("%s: snek is %v heading %v, want %v heading %v", test.desc, g.Snek.Body, g.Snek.Dir, test.wantBody, test.wantDir)
+		}
+		if got.Ate && (g.Food == got.Head || !g.foodCanGo(g.Food)) {
+			t.Errorf("%s: new food at %v, which isn't free", test.desc, g.Food)
+		}
+	}
+}
+
+func TestFollow(t *testing.T) {
+	tests := []struct {
+		desc       string
+		body       []Loc
+		head, tail Loc
+		wantBody   []Loc
+		wantDir    Direction
+	}{
+		{
+			desc: "first move",
+			head: Loc{5, 5}, tail: Loc{4, 5},
+			wantBody: []Loc{{5, 5}},
+		},
+		{
+			desc: "still coiled",
+			body: []Loc{{5, 5}}, head: Loc{6, 5}, tail: Loc{4, 5},
+			wantBody: []Loc{{5, 5}, {6, 5}}, wantDir: Right,
+		},
+		{
+			desc: "tail moves",
+			body: []Loc{{4, 5}, {5, 5}}, head: Loc{5, 4}, tail: Loc{4, 5},
+			wantBody: []Loc{{5, 5}, {5, 4}}, wantDir: Up,
+		},
+		{
+			desc: "wrap right",
+			body: []Loc{{46, 5}, {47, 5}}, head: Loc{0, 5}, tail: Loc{46, 5},
+			wantBody: []Loc{{47, 5}, {0, 5}}, wantDir: Right,
+		},
+		{
+			desc: "wrap up",
+			body: []Loc{{3, 1}, {3, 0}}, head: Loc{3, 47}, tail: Loc{3, 1},
+			wantBody: []Loc{{3, 0}, {3, 47}}, wantDir: Up,
+		},
+	}
+	for _, test := range tests {
+		s := &Snek{Body: append([]Loc(nil), test.body...)}
+		s.Follow(test.head, test.tail)
+		if !reflect.DeepEqual(s.Body, test.wantBody) || s.Dir != test.wantDir {
+			t.Errorf("%s: snek is %v heading %v, want %v heading %v", test.desc, s.Body, s.Dir, test.wantBody, test.wantDir)
+		}
+	}
+}
+
+func TestCloseIn(t *testing.T) {
+	tests := []struct {
+		desc   string
+		body   []Loc
+		food   Loc
+		margin int
+		wantOK bool
+		// Whether the food should have moved
+		wantFood bool
+	}{
+		{desc: "inside", body: []Loc{{5, 5}, {5, 6}}, food: Loc{3, 3}, margin: 2, wantOK: true},
+		{desc: "food outside", body: []Loc{{5, 5}, {5, 6}}, food: Loc{0, 3}, margin: 2, wantOK: true, wantFood: true},
+		{desc: "on the new edge", body: []Loc{{2, 5}, {2, 6}}, food: Loc{3, 3}, margin: 2, wantOK: true},
+		{desc: "head outside", body: []Loc{{2, 5}, {1, 5}}, food: Loc{3, 3}, margin: 2},
+		{desc: "tail outside", body: []Loc{{7, 5}, {8, 5}}, food: Loc{3, 3}, margin: 2},
+	}
+	for _, test := range tests {
+		g := &Game{
+			Board: Board{Width: 10, Height: 10},
+			Snek:  &Snek{Body: test.body, Dir: Right},
+			Food:  test.food,
+			rng:   rand.New(rand.NewSource(1)),
+		}
+		if ok := g.CloseIn(test.margin); ok != test.wantOK {
+			t.Errorf("%s: CloseIn(%d) = %t, want %t", test.desc, test.margin, ok, test.wantOK)
+		}
+		if g.Margin != test.margin {
+			t.Errorf("%s: margin is %d, want %d", test.desc, g.Margin, test.margin)
+		}
+		if moved := g.Food != test.food; moved != test.wantFood {
+			t.Errorf("%s: food moved from %v to %v, want it moved: %t", test.desc, test.food, g.Food, test.wantFood)
+		}
+		if !g.Open(g.Food) {
+			t.Errorf("%s: food at %v, which is out of play", test.desc, g.Food)
+		}
+	}
+}
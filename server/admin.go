@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"flag"
+	"log"
+	"sort"
+	"strings"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+var adminToken = flag.String("admin_token", "", "a token that grants access to the admin service, which is disabled if empty")
+
+// adminServer implements the admin service on top of a running server.
+type adminServer struct {
+	srv   *server
+	token string
+}
+
+func (a *adminServer) authorize(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tok := strings.TrimPrefix(first(md, "authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(tok), []byte(a.token)) != 1 {
+		return grpc.Errorf(codes.Unauthenticated, "invalid admin token")
+	}
+	return nil
+}
+
+func (a *adminServer) ListRooms(ctx context.Context, req *pb.ListRoomsRequest) (*pb.ListRoomsResponse, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	s := a.srv
+	s.Lock()
+	defer s.Unlock()
+	resp := &pb.ListRoomsResponse{}
+	for _, r := range s.rooms {
+		resp.Rooms = append(resp.Rooms, r.info())
+	}
+	sort.Slice(resp.Rooms, func(i, j int) bool { return resp.Rooms[i].Name < resp.Rooms[j].Name })
+	return resp, nil
+}
+
+func (r *room) info() *pb.RoomInfo {
+	r.Lock()
+	defer r.Unlock()
+	info := &pb.RoomInfo{
+		Name:   r.name,
+		Tick:   r.tick,
+		Config: r.config,
+	}
+	for _, snek := range r.sneks {
+		info.Players = append(info.Players, &pb.PlayerInfo{
+			Id:         int32(snek.id),
+			Name:       snek.name,
+			Addr:       snek.host,
+			QueueDepth: int32(len(snek.out.updates)),
+			Violations: int32(snek.moves.violations),
+			Dead:       snek.moves.dead,
+		})
+	}
+	sort.Slice(info.Players, func(i, j int) bool { return info.Players[i].Id < info.Players[j].Id })
+	return info
+}
+
+func (a *adminServer) Kick(ctx context.Context, req *pb.KickRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	n := a.srv.kick(func(s *snek) bool { return s.id == ID(req.Id) }, req.Reason)
+	if n == 0 {
+		return nil, grpc.Errorf(codes.NotFound, "no snek with id %d", req.Id)
+	}
+	log.Printf("Admin kicked snek %d: %s", req.Id, req.Reason)
+	return &pb.AdminResult{Affected: int32(n)}, nil
+}
+
+func (a *adminServer) Ban(ctx context.Context, req *pb.BanRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if req.Name == "" && req.Host == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "a ban needs a name or a host")
+	}
+
+	s := a.srv
+	s.Lock()
+	if req.Name != "" {
+		s.bannedNames[req.Name] = req.Reason
+	}
+	if req.Host != "" {
+		s.bannedHosts[req.Host] = req.Reason
+	}
+	s.Unlock()
+
+	n := s.kick(func(s *snek) bool {
+		return (req.Name != "" && s.name == req.Name) || (req.Host != "" && s.host == req.Host)
+	}, req.Reason)
+	log.Printf("Admin banned name %q, host %q: %s", req.Name, req.Host, req.Reason)
+	return &pb.AdminResult{Affected: int32(n)}, nil
+}
+
+// kick kicks every snek that matches, and returns how many there were.
+func (s *server) kick(match func(*snek) bool, reason string) int {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for _, r := range s.rooms {
+		r.Lock()
+		for _, snek := range r.sneks {
+			if match(snek) {
+				snek.out.kick(grpc.Errorf(codes.PermissionDenied, "kicked by an admin: %s", reason))
+				n++
+			}
+		}
+		r.Unlock()
+	}
+	return n
+}
+
+func (a *adminServer) EndGame(ctx context.Context, req *pb.EndGameRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	r, err := a.srv.room(req.Room)
+	if err != nil {
+		return nil, err
+	}
+	notice := "The game was ended by an admin"
+	if req.Reason != "" {
+		notice += ": " + req.Reason
+	}
+	log.Printf("Admin ended the game in room %q: %s", req.Room, req.Reason)
+	return &pb.AdminResult{Affected: int32(r.end(notice))}, nil
+}
+
+func (a *adminServer) ConfigureRoom(ctx context.Context, req *pb.ConfigureRoomRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if req.Config == nil || req.Config.TickMillis <= 0 {
+		return nil, grpc.Errorf(codes.InvalidArgument, "a room needs a positive tick rate")
+	}
+	if req.Config.Mode == pb.RoomMode_BATTLE_ROYALE && req.Config.Wrap {
+		return nil, grpc.Errorf(codes.InvalidArgument, "sneks can't wrap in a battle royale")
+	}
+	if req.Config.Teams < 0 || req.Config.Teams > maxMatchSize {
+		return nil, grpc.Errorf(codes.InvalidArgument, "a room can have at most %d teams", maxMatchSize)
+	}
+	if req.Config.Margin < 0 || int(req.Config.Margin) > maxMargin {
+		return nil, grpc.Errorf(codes.InvalidArgument, "a room's margin has to be between 0 and %d", maxMargin)
+	}
+	if req.Config.Margin > 0 && req.Config.Wrap {
+		return nil, grpc.Errorf(codes.InvalidArgument, "sneks can't wrap in a room with a margin")
+	}
+	r, err := a.srv.room(req.Room)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Admin configured room %q: %v", req.Room, req.Config)
+	return &pb.AdminResult{Affected: int32(r.configure(req.Config))}, nil
+}
+
+func (a *adminServer) Announce(ctx context.Context, req *pb.AnnounceRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	var rooms []*room
+	if req.Room != "" {
+		r, err := a.srv.room(req.Room)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, r)
+	} else {
+		a.srv.Lock()
+		for _, r := range a.srv.rooms {
+			rooms = append(rooms, r)
+		}
+		a.srv.Unlock()
+	}
+
+	n := 0
+	for _, r := range rooms {
+		n += r.announce(req.Message)
+	}
+	return &pb.AdminResult{Affected: int32(n)}, nil
+}
+
+func (s *server) room(name string) (*room, error) {
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.rooms[name]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "no room named %q", name)
+	}
+	return r, nil
+}
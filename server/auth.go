@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// Both credential files have one entry per line, blank lines and lines starting
+// with # are ignored. Each entry can be followed by a comma separated list of
+// the rooms it's allowed in, otherwise it can join any room.
+var (
+	tokenFile    = flag.String("tokens", "", "a file of pre-shared tokens players can join with, one '<token> <name> [rooms]' per line")
+	userFile     = flag.String("users", "", "a file of players that can join with a password, one '<name> <bcrypt hash> [rooms]' per line")
+	openRooms    = flag.String("open_rooms", "", "a comma separated list of rooms anyone can join without credentials, when -tokens or -users is set")
+	hashPassword = flag.Bool("hash_password", false, "read a password from stdin, print its hash for the -users file and exit")
+)
+
+// identity is who a snek has authenticated as.
+type identity struct {
+	name string
+	// The rooms they're allowed in, if empty they can go anywhere
+	rooms map[string]bool
+	// Set when we know the name is really theirs, because they proved it with a
+	// token or password or we gave it to them, rather than just saying so. Only
+	// verified players are rated.
+	verified bool
+}
+
+var anonymous = &identity{}
+
+func (id *identity) canJoin(room string) bool {
+	return len(id.rooms) == 0 || id.rooms[room]
+}
+
+type user struct {
+	*identity
+	hash []byte
+}
+
+type authenticator struct {
+	tokens map[string]*identity
+	users  map[string]*user
+	open   map[string]bool
+	// A hash we check passwords for unknown users against, so those failures
+	// take as long as ones for real users and don't give away who exists
+	dummyHash []byte
+}
+
+// loadAuthenticator returns nil if authentication isn't configured, in which
+// case anyone can join any room.
+func loadAuthenticator(tokenFile, userFile, openRooms string) (*authenticator, error) {
+	if tokenFile == "" && userFile == "" {
+		return nil, nil
+	}
+
+	a := &authenticator{
+		tokens: make(map[string]*identity),
+		users:  make(map[string]*user),
+		open:   splitRooms(openRooms),
+	}
+
+	if tokenFile != "" {
+		err := readEntries(tokenFile, func(key, name string, rooms map[string]bool) {
+			a.tokens[key] = &identity{name: name, rooms: rooms, verified: true}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if userFile != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte("snek"), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		a.dummyHash = hash
+
+		err = readEntries(userFile, func(name, hash string, rooms map[string]bool) {
+			a.users[name] = &user{
+				identity: &identity{name: name, rooms: rooms, verified: true},
+				hash:     []byte(hash),
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func readEntries(fn string, add func(a, b string, rooms map[string]bool)) error {
+	f, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		txt := strings.TrimSpace(sc.Text())
+		if txt == "" || strings.HasPrefix(txt, "#") {
+			continue
+		}
+		fields := strings.Fields(txt)
+		switch len(fields) {
+		case 2:
+			add(fields[0], fields[1], nil)
+		case 3:
+			add(fields[0], fields[1], splitRooms(fields[2]))
+		default:
+			return fmt.Errorf("%s:%d: expected 2 or 3 fields, got %d", fn, line, len(fields))
+		}
+	}
+	return sc.Err()
+}
+
+func splitRooms(list string) map[string]bool {
+	rooms := make(map[string]bool)
+	for _, r := range strings.Split(list, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			rooms[r] = true
+		}
+	}
+	return rooms
+}
+
+// authenticate checks the credentials in the request metadata, and returns who
+// they belong to if they're allowed in the given room. Players pass either an
+// "authorization: Bearer <token>" header, or a "username" and "password". If
+// authentication isn't configured, players can call themselves whatever they
+// like with a "name".
+func (a *authenticator) authenticate(ctx context.Context, room string) (*identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a == nil {
+		if name := first(md, "name"); name != "" {
+			return &identity{name: name}, nil
+		}
+		return anonymous, nil
+	}
+
+	id, err := a.identify(md)
+	if err != nil {
+		return nil, err
+	}
+
+	if id == anonymous {
+		if !a.open[room] {
+			return nil, grpc.Errorf(codes.Unauthenticated, "room %q requires a token or password", room)
+		}
+		return id, nil
+	}
+
+	if !id.canJoin(room) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "%s isn't allowed in room %q", id.name, room)
+	}
+	return id, nil
+}
+
+// name returns who the request metadata says the caller is, or an empty string
+// if it doesn't say or we don't believe it.
+func (a *authenticator) name(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a == nil {
+		return first(md, "name")
+	}
+	id, err := a.identify(md)
+	if err != nil {
+		return ""
+	}
+	return id.name
+}
+
+// verifiedName is like name, but only returns names the caller has proved are
+// theirs, so it's empty for everyone when authentication isn't configured.
+func (a *authenticator) verifiedName(ctx context.Context) string {
+	if a == nil {
+		return ""
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := a.identify(md)
+	if err != nil || !id.verified {
+		return ""
+	}
+	return id.name
+}
+
+func (a *authenticator) identify(md metadata.MD) (*identity, error) {
+	if v := first(md, "authorization"); v != "" {
+		tok := strings.TrimPrefix(v, "Bearer ")
+		id, ok := a.tokens[tok]
+		if !ok {
+			return nil, grpc.Errorf(codes.Unauthenticated, "invalid token")
+		}
+		return id, nil
+	}
+
+	if name := first(md, "username"); name != "" {
+		u, ok := a.users[name]
+		if !ok {
+			bcrypt.CompareHashAndPassword(a.dummyHash, []byte(first(md, "password")))
+			return nil, grpc.Errorf(codes.Unauthenticated, "invalid username or password")
+		}
+		if err := bcrypt.CompareHashAndPassword(u.hash, []byte(first(md, "password"))); err != nil {
+			return nil, grpc.Errorf(codes.Unauthenticated, "invalid username or password")
+		}
+		return u.identity, nil
+	}
+
+	return anonymous, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if vs := md[key]; len(vs) > 0 {
+		return vs[0]
+	}
+	return ""
+}
+
+func printPasswordHash() error {
+	sc := bufio.NewScanner(os.Stdin)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return err
+		}
+		return fmt.Errorf("no password given")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(sc.Text()), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(hash))
+	return nil
+}
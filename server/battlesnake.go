@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+// The Battlesnake webhook API, see https://docs.battlesnake.com/api. Their
+// board has its origin in the bottom left corner with y going up, where ours is
+// in the top left with y going down, so coordinates get flipped on the way in
+// and out.
+
+type bsGameState struct {
+	Game  bsGame  `json:"game"`
+	Turn  int64   `json:"turn"`
+	Board bsBoard `json:"board"`
+	You   bsSnake `json:"you"`
+}
+
+type bsGame struct {
+	ID      string    `json:"id"`
+	Ruleset bsRuleset `json:"ruleset"`
+	Map     string    `json:"map"`
+	Timeout int64     `json:"timeout"`
+	Source  string    `json:"source"`
+}
+
+type bsRuleset struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type bsBoard struct {
+	Height  int       `json:"height"`
+	Width   int       `json:"width"`
+	Food    []bsCoord `json:"food"`
+	Hazards []bsCoord `json:"hazards"`
+	Snakes  []bsSnake `json:"snakes"`
+}
+
+type bsSnake struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Health  int       `json:"health"`
+	Body    []bsCoord `json:"body"`
+	Latency string    `json:"latency"`
+	Head    bsCoord   `json:"head"`
+	Length  int       `json:"length"`
+	Shout   string    `json:"shout"`
+}
+
+type bsCoord struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type bsMoveResponse struct {
+	Move  string `json:"move"`
+	Shout string `json:"shout"`
+}
+
+func toBSCoord(l rules.Loc) bsCoord {
+	return bsCoord{X: l.X, Y: pb.BoardHeight - 1 - l.Y}
+}
+
+// toBSSnake turns a body, with its head at the end like our clients keep it,
+// into a Battlesnake, which has its head first.
+func toBSSnake(id ID, name string, body []rules.Loc) bsSnake {
+	s := bsSnake{
+		ID:      fmt.Sprint(id),
+		Name:    name,
+		Health:  100,
+		Latency: "0",
+		Length:  len(body),
+	}
+	for i := len(body) - 1; i >= 0; i-- {
+		s.Body = append(s.Body, toBSCoord(body[i]))
+	}
+	if len(s.Body) > 0 {
+		s.Head = s.Body[0]
+	}
+	return s
+}
+
+// Battlesnake moves, in our coordinates.
+var bsMoves = map[string]rules.Direction{
+	"up":    rules.Up,
+	"down":  rules.Down,
+	"left":  rules.Left,
+	"right": rules.Right,
+}
+
+// bsClient calls a Battlesnake bot's webhooks.
+type bsClient struct {
+	url string
+}
+
+func (c *bsClient) post(ctx context.Context, path string, state *bsGameState) ([]byte, error) {
+	body, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(c.url, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		return nil, fmt.Errorf("%s returned %s", path, resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+}
+
+func (c *bsClient) start(ctx context.Context, state *bsGameState) error {
+	_, err := c.post(ctx, "/start", state)
+	return err
+}
+
+func (c *bsClient) end(ctx context.Context, state *bsGameState) error {
+	_, err := c.post(ctx, "/end", state)
+	return err
+}
+
+// move asks the bot which way to go next, and returns it as a direction on our
+// board.
+func (c *bsClient) move(ctx context.Context, state *bsGameState) (rules.Direction, error) {
+	body, err := c.post(ctx, "/move", state)
+	if err != nil {
+		return rules.Direction{}, err
+	}
+	var resp bsMoveResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return rules.Direction{}, fmt.Errorf("bad /move response: %v", err)
+	}
+	d, ok := bsMoves[strings.ToLower(resp.Move)]
+	if !ok {
+		return rules.Direction{}, fmt.Errorf("unknown move %q", resp.Move)
+	}
+	return d, nil
+}
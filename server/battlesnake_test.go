@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+func TestBSClientMove(t *testing.T) {
+	var got bsGameState
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/move" {
+			t.Errorf("bot got a request for %s, want /move", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("bot got a bad game state: %v", err)
+		}
+		fmt.Fprint(w, `{"move": "Up", "shout": "hi"}`)
+	}))
+	defer srv.Close()
+
+	c := &bsClient{url: srv.URL + "/"}
+	state := &bsGameState{Turn: 7, You: toBSSnake(3, "bot", []rules.Loc{{X: 1, Y: 2}, {X: 2, Y: 2}})}
+	d, err := c.move(context.Background(), state)
+	if err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if d != rules.Up {
+		t.Errorf("move = %v, want %v", d, rules.Up)
+	}
+	if got.Turn != 7 || got.You.ID != "3" {
+		t.Errorf("bot got turn %d for snake %q, want turn 7 for snake \"3\"", got.Turn, got.You.ID)
+	}
+	// Heads go first, and y goes up from the bottom
+	if want := (bsCoord{X: 2, Y: pb.BoardHeight - 3}); got.You.Head != want {
+		t.Errorf("bot got its head at %v, want %v", got.You.Head, want)
+	}
+}
+
+func TestBSClientMoveErrors(t *testing.T) {
+	tests := []struct {
+		desc   string
+		status int
+		body   string
+	}{
+		{"server error", http.StatusInternalServerError, `{"move": "up"}`},
+		{"not found", http.StatusNotFound, ""},
+		{"malformed body", http.StatusOK, `{"move": `},
+		{"not json", http.StatusOK, "up"},
+		{"unknown move", http.StatusOK, `{"move": "sideways"}`},
+		{"no move", http.StatusOK, `{}`},
+	}
+	for _, test := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(test.status)
+			fmt.Fprint(w, test.body)
+		}))
+		c := &bsClient{url: srv.URL}
+		if d, err := c.move(context.Background(), &bsGameState{}); err == nil {
+			t.Errorf("%s: move = %v, want an error", test.desc, d)
+		}
+		srv.Close()
+	}
+}
+
+func TestBotStreamFallsBackOnTimeout(t *testing.T) {
+	defer func(d time.Duration) { *botTimeout = d }(*botTimeout)
+	*botTimeout = 50 * time.Millisecond
+
+	// The bot turns down once, then stops answering in time
+	var mu sync.Mutex
+	moves := 0
+	hang := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/move" {
+			return
+		}
+		mu.Lock()
+		moves++
+		n := moves
+		mu.Unlock()
+		if n > 1 {
+			<-hang
+			return
+		}
+		fmt.Fprint(w, `{"move": "down"}`)
+	}))
+	defer srv.Close()
+	defer close(hang)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := newBotStream(ctx, "test", "bot", srv.URL)
+	b.joined(1)
+	b.Send(&pb.UpdateResponse{Spawn: &pb.Loc{X: 10, Y: 10}})
+
+	for tick, want := range []rules.Loc{{X: 10, Y: 11}, {X: 10, Y: 12}, {X: 10, Y: 13}} {
+		b.Send(&pb.UpdateResponse{Tick: int64(tick + 1)})
+		req, err := b.Recv()
+		if err != nil {
+			t.Fatalf("tick %d: Recv: %v", tick+1, err)
+		}
+		if req.Dead {
+			t.Fatalf("tick %d: bot died", tick+1)
+		}
+		if got := ruleLoc(req.NewHead); got != want {
+			t.Errorf("tick %d: bot moved to %v, want %v", tick+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+var (
+	botList    = flag.String("bots", "", "a comma separated list of Battlesnake bots for the server to play, each '[room/]name=url'")
+	botTimeout = flag.Duration("bot_timeout", 0, "how long to wait for a bot's move before making one for it, defaults to the room's tick rate")
+	botRespawn = flag.Duration("bot_respawn", 3*time.Second, "how long bots from -bots wait to rejoin after they die")
+)
+
+// botStream plays a snek for a Battlesnake bot. The room sends it updates like
+// any other snek, and each tick it asks the bot where to go and hands the move
+// back to the room, playing by the same rules as our clients.
+type botStream struct {
+	ctx    context.Context
+	client *bsClient
+	room   string
+	name   string
+	// The latest tick we haven't moved on yet
+	ticks chan int64
+
+	mu         sync.Mutex
+	id         ID
+	game       *rules.Game
+	tickMillis int64
+	latency    time.Duration
+	started    bool
+	ended      bool
+	dead       bool
+	// How far the arena has closed in, as of the latest tick
+	margin int
+	// Everyone else's sneks, built up from their moves
+	others   map[ID]*rules.Snek
+	failures int
+}
+
+func newBotStream(ctx context.Context, room, name, url string) *botStream {
+	return &botStream{
+		ctx:        ctx,
+		client:     &bsClient{url: url},
+		room:       room,
+		name:       name,
+		ticks:      make(chan int64, 1),
+		game:       rules.NewGame(rules.NewBoard(*wrap), rand.New(rand.NewSource(time.Now().UnixNano()))),
+		tickMillis: int64(*tickRate / time.Millisecond),
+		others:     make(map[ID]*rules.Snek),
+	}
+}
+
+// joined is called once the bot's snek is in a room, before it gets any
+// updates.
+func (b *botStream) joined(id ID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.id = id
+}
+
+func (b *botStream) Context() context.Context {
+	return b.ctx
+}
+
+// Send keeps track of the board as the room tells us about it.
+func (b *botStream) Send(resp *pb.UpdateResponse) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case resp.Id == 0:
+		if resp.Config != nil {
+			b.game.Wrap, b.tickMillis = resp.Config.Wrap, resp.Config.TickMillis
+		}
+		if resp.Spawn != nil {
+			b.game.Respawn(ruleLoc(resp.Spawn))
+		}
+		if resp.Tick > 0 {
+			b.margin = int(resp.Margin)
+			// Only the latest tick matters, if the bot is slow it skips the rest
+			select {
+			case <-b.ticks:
+			default:
+			}
+			b.ticks <- resp.Tick
+		}
+	case resp.Ack:
+	case resp.Dead && ID(resp.Id) == b.id:
+		// The room killed us
+		b.dead = true
+	case resp.Dead:
+		delete(b.others, ID(resp.Id))
+	default:
+		o, ok := b.others[ID(resp.Id)]
+		if !ok {
+			o = &rules.Snek{}
+			b.others[ID(resp.Id)] = o
+		}
+		o.Follow(ruleLoc(resp.NewHead), ruleLoc(resp.OldTail))
+	}
+	return nil
+}
+
+// Recv waits for the next tick, and returns the bot's move for it.
+func (b *botStream) Recv() (*pb.UpdateRequest, error) {
+	b.mu.Lock()
+	dead, started := b.dead, b.started
+	b.started = true
+	b.mu.Unlock()
+	if dead {
+		return nil, io.EOF
+	}
+	if !started {
+		ctx, cancel := context.WithTimeout(b.ctx, b.timeout())
+		if err := b.client.start(ctx, b.state()); err != nil {
+			log.Printf("[%s] bot %q: /start: %v", b.room, b.name, err)
+		}
+		cancel()
+	}
+
+	var tick int64
+	select {
+	case tick = <-b.ticks:
+	case <-b.ctx.Done():
+		return nil, b.ctx.Err()
+	}
+	b.mu.Lock()
+	dead = b.dead
+	b.mu.Unlock()
+	if dead {
+		return nil, io.EOF
+	}
+	if !b.closeIn() {
+		return &pb.UpdateRequest{Tick: tick, Dead: true}, nil
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout())
+	dir, err := b.client.move(ctx, b.state())
+	cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latency = time.Since(start)
+	if err == nil && dir == b.game.Snek.Dir.Opposite() {
+		err = fmt.Errorf("can't reverse direction")
+	}
+	if err != nil {
+		// Only log now and then, a bot that's down would fail every tick
+		if b.failures%100 == 0 {
+			log.Printf("[%s] bot %q: /move: %v, moving for it", b.room, b.name, err)
+		}
+		b.failures++
+		dir = b.fallback()
+	}
+
+	b.game.Tick = tick - 1
+	m, ok := b.game.Step(dir)
+	if !ok {
+		b.dead = true
+		return &pb.UpdateRequest{Tick: tick, Dead: true}, nil
+	}
+	return &pb.UpdateRequest{
+		NewHead: &pb.Loc{X: int32(m.Head.X), Y: int32(m.Head.Y)},
+		OldTail: &pb.Loc{X: int32(m.Tail.X), Y: int32(m.Tail.Y)},
+		Tick:    tick,
+	}, nil
+}
+
+// closeIn catches the bot's board up with the arena closing in, and returns
+// false if the bot was caught outside.
+func (b *botStream) closeIn() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.game.CloseIn(b.margin) {
+		return true
+	}
+	b.dead = true
+	return false
+}
+
+func ruleLoc(l *pb.Loc) rules.Loc {
+	return rules.Loc{X: int(l.GetX()), Y: int(l.GetY())}
+}
+
+// fallback picks a move for the bot when it doesn't give us one in time, going
+// straight if it can, otherwise turning away from whatever's in the way. It
+// must be called with the bot locked.
+func (b *botStream) fallback() rules.Direction {
+	s := b.game.Snek
+	dirs := []rules.Direction{s.Dir, {X: s.Dir.Y, Y: -s.Dir.X}, {X: -s.Dir.Y, Y: s.Dir.X}}
+	// Try to stay clear of everyone, then just ourselves
+	for _, avoidOthers := range []bool{true, false} {
+		for _, d := range dirs {
+			n, ok := b.game.Next(s.Head(), d)
+			if ok && !s.Covers(n) && !(avoidOthers && b.onOthers(n)) {
+				return d
+			}
+		}
+	}
+	return s.Dir
+}
+
+func (b *botStream) onOthers(l rules.Loc) bool {
+	for _, o := range b.others {
+		if o.Covers(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *botStream) timeout() time.Duration {
+	if *botTimeout > 0 {
+		return *botTimeout
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Duration(b.tickMillis) * time.Millisecond
+}
+
+// state describes the board for the bot in Battlesnake terms.
+func (b *botStream) state() *bsGameState {
+	timeout := b.timeout()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	you := toBSSnake(b.id, b.name, b.game.Snek.Body)
+	you.Latency = fmt.Sprint(int64(b.latency / time.Millisecond))
+	state := &bsGameState{
+		Game: bsGame{
+			ID:      b.room,
+			Ruleset: bsRuleset{Name: "snek"},
+			Timeout: int64(timeout / time.Millisecond),
+			Source:  "snek",
+		},
+		Turn: b.game.Tick,
+		Board: bsBoard{
+			Height: pb.BoardHeight,
+			Width:  pb.BoardWidth,
+			Food:   []bsCoord{toBSCoord(b.game.Food)},
+			Snakes: []bsSnake{you},
+		},
+		You: you,
+	}
+	if b.game.Wrap {
+		state.Game.Ruleset.Name = "wrapped"
+	}
+	for id, o := range b.others {
+		state.Board.Snakes = append(state.Board.Snakes, toBSSnake(id, "", o.Body))
+	}
+	// Bots know to stay out of hazards, so everything outside the arena is one
+	for x := 0; x < b.game.Width; x++ {
+		for y := 0; y < b.game.Height; y++ {
+			if l := (rules.Loc{X: x, Y: y}); !b.game.InBounds(l) {
+				state.Board.Hazards = append(state.Board.Hazards, toBSCoord(l))
+			}
+		}
+	}
+	return state
+}
+
+// end tells the bot the game is over, if it hasn't been told already.
+func (b *botStream) end() {
+	b.mu.Lock()
+	ended := b.ended || !b.started
+	b.ended = true
+	b.mu.Unlock()
+	if ended {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := b.client.end(ctx, b.state()); err != nil {
+		log.Printf("[%s] bot %q: /end: %v", b.room, b.name, err)
+	}
+}
+
+// addBot plays a Battlesnake bot in a room until it dies or the room ends. If
+// respawn is set, the bot rejoins a little while after dying.
+func (s *server) addBot(room, name, botURL string, respawn bool) {
+	host := botURL
+	if u, err := url.Parse(botURL); err == nil {
+		host = u.Hostname()
+	}
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		b := newBotStream(ctx, room, name, botURL)
+		err := s.play(b, room, &identity{name: name, verified: true}, host)
+		cancel()
+		b.end()
+		if err != nil {
+			log.Printf("[%s] bot %q: %v", room, name, err)
+			return
+		}
+
+		b.mu.Lock()
+		dead := b.dead
+		b.mu.Unlock()
+		if !respawn || !dead {
+			return
+		}
+		time.Sleep(*botRespawn)
+	}
+}
+
+// startBots starts the bots from the -bots flag.
+func (s *server) startBots(list string) error {
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.Index(entry, "=")
+		if i <= 0 {
+			return fmt.Errorf("bot %q should look like '[room/]name=url'", entry)
+		}
+		room, name, botURL := defaultRoom, entry[:i], entry[i+1:]
+		if j := strings.Index(name, "/"); j >= 0 {
+			room, name = name[:j], name[j+1:]
+		}
+		go s.addBot(room, name, botURL, true)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bcspragu/Snek/levels"
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var dailyFile = flag.String("daily", "", "a file to keep daily challenge results in, empty to only keep them until the server restarts")
+
+const (
+	dateFormat = "2006-01-02"
+	// How many days of daily challenge results we keep
+	maxDays = 30
+)
+
+// dailies are the results of the daily challenges, by day. Everyone plays the
+// same game on their own, telling us when they start, then sending us their
+// moves so we can check how they did. Players only get one attempt each day.
+type dailies struct {
+	sync.Mutex
+	file string
+	days map[string][]*pb.DailyScore
+	// Signals the saver that there's something new to write out
+	dirty chan struct{}
+}
+
+func loadDailies(file string) (*dailies, error) {
+	d := &dailies{
+		file:  file,
+		days:  make(map[string][]*pb.DailyScore),
+		dirty: make(chan struct{}, 1),
+	}
+	if file == "" {
+		return d, nil
+	}
+
+	buf, err := ioutil.ReadFile(file)
+	switch {
+	case os.IsNotExist(err):
+		// We'll make it the first time someone plays
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(buf, &d.days); err != nil {
+			return nil, err
+		}
+	}
+	go d.saveChanges()
+	return d, nil
+}
+
+// start records that a player has started a day's challenge, and returns false
+// if they already have.
+func (d *dailies) start(date, name string) bool {
+	d.Lock()
+	defer d.Unlock()
+	for _, s := range d.days[date] {
+		if s.Name == name {
+			return false
+		}
+	}
+	d.days[date] = append(d.days[date], &pb.DailyScore{Name: name, StartedUnix: time.Now().Unix()})
+	d.prune()
+	d.changed()
+	return true
+}
+
+// finish records the result of the attempt a player started on a day, and
+// returns where it placed and how many players have finished.
+func (d *dailies) finish(date, name string, length int) (int, int, error) {
+	d.Lock()
+	defer d.Unlock()
+	var score *pb.DailyScore
+	for _, s := range d.days[date] {
+		if s.Name == name {
+			score = s
+		}
+	}
+	switch {
+	case score == nil:
+		return 0, 0, grpc.Errorf(codes.FailedPrecondition, "%s didn't start the daily challenge for %s", name, date)
+	case score.Finished:
+		return 0, 0, grpc.Errorf(codes.AlreadyExists, "%s has already played the daily challenge for %s, only the first attempt counts", name, date)
+	}
+	score.Length = int32(length)
+	score.TimeUnix = time.Now().Unix()
+	score.Finished = true
+
+	all := d.ranked(date)
+	rank := 0
+	for i, s := range all {
+		if s.Name == name {
+			rank = i + 1
+		}
+	}
+	d.changed()
+	return rank, len(all), nil
+}
+
+// changed lets the saver know there's something new to write out. It must be
+// called with the dailies locked.
+func (d *dailies) changed() {
+	select {
+	case d.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// prune forgets the oldest days once we have more than we keep. It must be
+// called with the dailies locked.
+func (d *dailies) prune() {
+	var dates []string
+	for date := range d.days {
+		dates = append(dates, date)
+	}
+	if len(dates) <= maxDays {
+		return
+	}
+	sort.Strings(dates)
+	for _, date := range dates[:len(dates)-maxDays] {
+		delete(d.days, date)
+	}
+}
+
+// ranked returns copies of a day's finished results, best first, with their
+// ranks filled in. Ties go to whoever finished first. It must be called with
+// the dailies locked.
+func (d *dailies) ranked(date string) []*pb.DailyScore {
+	var all []*pb.DailyScore
+	for _, s := range d.days[date] {
+		if s.Finished {
+			all = append(all, proto.Clone(s).(*pb.DailyScore))
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Length != all[j].Length {
+			return all[i].Length > all[j].Length
+		}
+		return all[i].TimeUnix < all[j].TimeUnix
+	})
+	for i, s := range all {
+		s.Rank = int32(i + 1)
+	}
+	return all
+}
+
+// saveChanges writes the results out whenever they change.
+func (d *dailies) saveChanges() {
+	for range d.dirty {
+		if err := d.save(); err != nil {
+			log.Printf("failed to save daily challenge results to %s: %v", d.file, err)
+		}
+	}
+}
+
+func (d *dailies) save() error {
+	d.Lock()
+	buf, err := json.MarshalIndent(d.days, "", "  ")
+	d.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(d.file+".tmp", buf, 0644); err != nil {
+		return err
+	}
+	return os.Rename(d.file+".tmp", d.file)
+}
+
+// checkDate makes sure a daily challenge's date is one that's being played
+// right now somewhere, which is today in UTC, give or take a day for anyone
+// who started just before midnight.
+func checkDate(date string) error {
+	day, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return grpc.Errorf(codes.InvalidArgument, "dates look like YYYY-MM-DD, got %q", date)
+	}
+	today, _ := time.Parse(dateFormat, time.Now().UTC().Format(dateFormat))
+	if diff := today.Sub(day); diff < -24*time.Hour || diff > 24*time.Hour {
+		return grpc.Errorf(codes.FailedPrecondition, "the daily challenge for %s isn't open", date)
+	}
+	return nil
+}
+
+// replayDaily plays a day's challenge with the given moves, and returns how
+// long the snek got.
+func replayDaily(day time.Time, moves []pb.PhoneType) (int, error) {
+	if len(moves) > levels.DailyTicks {
+		return 0, grpc.Errorf(codes.InvalidArgument, "the daily challenge only lasts %d ticks, got %d moves", levels.DailyTicks, len(moves))
+	}
+	dirs := make([]rules.Direction, len(moves))
+	for i, mv := range moves {
+		if mv < 0 || int(mv) >= len(rules.Directions) {
+			return 0, grpc.Errorf(codes.InvalidArgument, "%d isn't a direction", mv)
+		}
+		dirs[i] = rules.Directions[mv]
+	}
+	g, taken := levels.Daily(day).Replay(dirs)
+	if taken < len(dirs) {
+		return 0, grpc.Errorf(codes.InvalidArgument, "the snek died on tick %d, but kept moving", taken)
+	}
+	return len(g.Snek.Body), nil
+}
+
+func (s *server) StartDaily(ctx context.Context, req *pb.DailyRequest) (*pb.DailyStarted, error) {
+	name := s.auth.verifiedName(ctx)
+	if name == "" {
+		return nil, grpc.Errorf(codes.Unauthenticated, "the daily challenge needs a token or password to put you on the leaderboard")
+	}
+	if err := checkDate(req.Date); err != nil {
+		return nil, err
+	}
+	if !s.daily.start(req.Date, name) {
+		return nil, grpc.Errorf(codes.AlreadyExists, "%s has already played the daily challenge for %s, only the first attempt counts", name, req.Date)
+	}
+	log.Printf("%s started the daily challenge for %s", name, req.Date)
+	return &pb.DailyStarted{Ticks: levels.DailyTicks}, nil
+}
+
+func (s *server) SubmitDaily(ctx context.Context, req *pb.DailyResult) (*pb.DailyStanding, error) {
+	name := s.auth.verifiedName(ctx)
+	if name == "" {
+		return nil, grpc.Errorf(codes.Unauthenticated, "the daily challenge needs a token or password to put you on the leaderboard")
+	}
+	if err := checkDate(req.Date); err != nil {
+		return nil, err
+	}
+	day, _ := time.Parse(dateFormat, req.Date)
+	length, err := replayDaily(day, req.Moves)
+	if err != nil {
+		return nil, err
+	}
+	if int(req.Length) != length {
+		return nil, grpc.Errorf(codes.InvalidArgument, "those moves get to a length of %d, not %d", length, req.Length)
+	}
+	rank, players, err := s.daily.finish(req.Date, name, length)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("%s got to a length of %d in the daily challenge for %s", name, length, req.Date)
+	return &pb.DailyStanding{Rank: int32(rank), Players: int32(players)}, nil
+}
+
+func (s *server) DailyLeaderboard(ctx context.Context, req *pb.DailyRequest) (*pb.DailyLeaderboardResponse, error) {
+	date := req.Date
+	if date == "" {
+		date = time.Now().UTC().Format(dateFormat)
+	}
+	if _, err := time.Parse(dateFormat, date); err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "dates look like YYYY-MM-DD, got %q", date)
+	}
+	s.daily.Lock()
+	all := s.daily.ranked(date)
+	s.daily.Unlock()
+	if req.Limit > 0 && int(req.Limit) < len(all) {
+		all = all[:req.Limit]
+	}
+	return &pb.DailyLeaderboardResponse{Date: date, Scores: all}, nil
+}
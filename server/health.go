@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var (
+	healthInterval = flag.Duration("health_interval", time.Second, "how often to check that every room's clock is ticking")
+	stallTicks     = flag.Int("stall_ticks", 10, "how many ticks a room's clock can miss before the server reports itself as not serving")
+)
+
+// healthServices returns the services we report health for, which are the ones
+// we're running. The empty name is the server as a whole.
+func healthServices() []string {
+	svcs := []string{"", "snek.Snek"}
+	if *adminToken != "" {
+		svcs = append(svcs, "snek.Admin")
+	}
+	return svcs
+}
+
+// stalled returns the names of rooms whose clocks have stopped ticking.
+func (s *server) stalled() []string {
+	s.Lock()
+	defer s.Unlock()
+	var names []string
+	for _, r := range s.rooms {
+		r.Lock()
+		if time.Since(r.lastTick) > time.Duration(*stallTicks)*r.tickRate() {
+			names = append(names, r.name)
+		}
+		r.Unlock()
+	}
+	return names
+}
+
+// watchHealth keeps the health service up to date: we're serving as long as
+// every room's clock is ticking, and not serving once we start shutting down.
+func (s *server) watchHealth(d time.Duration) {
+	status := healthpb.HealthCheckResponse_UNKNOWN
+	set := func(st healthpb.HealthCheckResponse_ServingStatus) {
+		if st == status {
+			return
+		}
+		status = st
+		for _, svc := range healthServices() {
+			s.health.SetServingStatus(svc, st)
+		}
+	}
+
+	set(healthpb.HealthCheckResponse_SERVING)
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for range t.C {
+		s.Lock()
+		draining := s.draining
+		s.Unlock()
+		if draining {
+			// shutdown has already marked everything as not serving
+			return
+		}
+
+		if rooms := s.stalled(); len(rooms) > 0 {
+			if status != healthpb.HealthCheckResponse_NOT_SERVING {
+				log.Printf("Clocks have stalled in rooms %q, reporting not serving", rooms)
+			}
+			set(healthpb.HealthCheckResponse_NOT_SERVING)
+			continue
+		}
+		if status == healthpb.HealthCheckResponse_NOT_SERVING {
+			log.Printf("Every room's clock is ticking again, reporting serving")
+		}
+		set(healthpb.HealthCheckResponse_SERVING)
+	}
+}
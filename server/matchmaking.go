@@ -0,0 +1,351 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	matchInterval = flag.Duration("match_interval", time.Second, "how often the matchmaker tries to group waiting players")
+	matchBand     = flag.Float64("match_band", 100, "how far apart in rating players can be when they first start waiting for a match")
+	matchWiden    = flag.Float64("match_widen", 10, "how much further apart in rating players can be for every second they wait")
+	matchBots     = flag.String("match_bots", "", "a comma separated list of Battlesnake bots to fill matches with, each 'name=url'")
+	matchBotWait  = flag.Duration("match_bot_wait", 30*time.Second, "how long players wait for other players before a match is filled with bots")
+)
+
+// The most players we'll put in a single match
+const maxMatchSize = 8
+
+// ticket is a player waiting for a match.
+type ticket struct {
+	name   string
+	rating float64
+	mode   string
+	size   int
+	queued time.Time
+	// Gets the player's match once it's been found
+	found chan *pb.MatchUpdate
+}
+
+// band is how far from the player's rating we'll look for opponents, which
+// grows the longer they wait.
+func (t *ticket) band(now time.Time) float64 {
+	return *matchBand + *matchWiden*now.Sub(t.queued).Seconds()
+}
+
+// wants returns whether two players are waiting for the same kind of game.
+func (t *ticket) wants(o *ticket) bool {
+	return t.mode == o.mode && t.size == o.size
+}
+
+// matchmaker groups waiting players into matches with others of a similar
+// rating.
+type matchmaker struct {
+	sync.Mutex
+	// Oldest first
+	queue []*ticket
+	// How many matches we've made, to name their rooms
+	made int
+}
+
+func (m *matchmaker) enqueue(t *ticket) error {
+	m.Lock()
+	defer m.Unlock()
+	for _, q := range m.queue {
+		if q.name == t.name {
+			return grpc.Errorf(codes.AlreadyExists, "%s is already waiting for a match", t.name)
+		}
+	}
+	m.queue = append(m.queue, t)
+	return nil
+}
+
+// dequeue takes a player out of the queue, if they're still in it.
+func (m *matchmaker) dequeue(t *ticket) {
+	m.Lock()
+	defer m.Unlock()
+	for i, q := range m.queue {
+		if q == t {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// status returns how a waiting player's search is going.
+func (m *matchmaker) status(t *ticket) *pb.MatchUpdate {
+	m.Lock()
+	defer m.Unlock()
+	now := time.Now()
+	var queued int32
+	for _, q := range m.queue {
+		if q.wants(t) {
+			queued++
+		}
+	}
+	return &pb.MatchUpdate{
+		WaitedSeconds: int64(now.Sub(t.queued) / time.Second),
+		Band:          t.band(now),
+		Queued:        queued,
+	}
+}
+
+// match is a group of players that are going to play each other.
+type match struct {
+	room    string
+	mode    string
+	tickets []*ticket
+	// Names of the bots filling out the match, and their URLs
+	bots map[string]string
+}
+
+// group takes every group of players it can make a match from out of the
+// queue. The players who've been waiting longest get first pick, and only play
+// opponents that are within both their own band and the opponent's. Players
+// that have waited long enough get bots to fill out their match.
+func (m *matchmaker) group(bots []*pb.Entrant) []*match {
+	m.Lock()
+	defer m.Unlock()
+	now := time.Now()
+	used := make(map[*ticket]bool)
+	var matches []*match
+	for _, t := range m.queue {
+		if used[t] {
+			continue
+		}
+		var near []*ticket
+		for _, o := range m.queue {
+			if o == t || used[o] || !o.wants(t) {
+				continue
+			}
+			if math.Abs(o.rating-t.rating) <= math.Min(t.band(now), o.band(now)) {
+				near = append(near, o)
+			}
+		}
+		sort.SliceStable(near, func(i, j int) bool {
+			return math.Abs(near[i].rating-t.rating) < math.Abs(near[j].rating-t.rating)
+		})
+		if len(near) > t.size-1 {
+			near = near[:t.size-1]
+		}
+
+		group := append([]*ticket{t}, near...)
+		fill := t.size - len(group)
+		if fill > 0 && (len(bots) == 0 || now.Sub(t.queued) < *matchBotWait) {
+			continue
+		}
+
+		m.made++
+		mt := &match{
+			room:    fmt.Sprintf("match-%d", m.made),
+			mode:    t.mode,
+			tickets: group,
+			bots:    make(map[string]string),
+		}
+		for i := 0; i < fill; i++ {
+			b := bots[i%len(bots)]
+			name := b.Name
+			if i >= len(bots) {
+				name = fmt.Sprintf("%s-%d", b.Name, i/len(bots)+1)
+			}
+			mt.bots[name] = b.BotUrl
+		}
+		for _, g := range group {
+			used[g] = true
+		}
+		matches = append(matches, mt)
+	}
+
+	var left []*ticket
+	for _, t := range m.queue {
+		if !used[t] {
+			left = append(left, t)
+		}
+	}
+	m.queue = left
+	return matches
+}
+
+// parseBots parses the -match_bots flag.
+func parseBots(list string) ([]*pb.Entrant, error) {
+	var bots []*pb.Entrant
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		i := strings.Index(entry, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("bot %q should look like 'name=url'", entry)
+		}
+		bots = append(bots, &pb.Entrant{Name: entry[:i], BotUrl: entry[i+1:]})
+	}
+	return bots, nil
+}
+
+// modeConfig returns the rules for a kind of game players can queue for.
+func modeConfig(mode string) (*pb.RoomConfig, error) {
+	cfg := &pb.RoomConfig{TickMillis: int64(*tickRate / time.Millisecond)}
+	switch mode {
+	case "classic":
+	case "wrap":
+		cfg.Wrap = true
+	case "royale":
+		cfg.Mode = pb.RoomMode_BATTLE_ROYALE
+	case "teams":
+		cfg.Mode = pb.RoomMode_TEAMS
+	default:
+		return nil, grpc.Errorf(codes.InvalidArgument, "unknown mode %q, must be 'classic', 'wrap', 'royale' or 'teams'", mode)
+	}
+	return cfg, nil
+}
+
+// runMatchmaker makes matches out of the queue until the server shuts down.
+func (s *server) runMatchmaker(interval time.Duration, bots []*pb.Entrant) {
+	for range time.Tick(interval) {
+		if s.isDraining() {
+			return
+		}
+		for _, m := range s.matchmaker.group(bots) {
+			s.startMatch(m)
+		}
+	}
+}
+
+// startMatch sets up the room for a match, and tells the players where it is.
+func (s *server) startMatch(m *match) {
+	var players []string
+	for _, t := range m.tickets {
+		players = append(players, t.name)
+	}
+	for name := range m.bots {
+		players = append(players, name)
+	}
+	// Only the players we matched can join, so nobody can sneak into a ranked
+	// game by guessing its room
+	roster := make(map[string]bool)
+	for _, p := range players {
+		roster[p] = true
+	}
+	cfg, _ := modeConfig(m.mode)
+	s.setRoomConfig(m.room, cfg, roster)
+	for name, url := range m.bots {
+		go s.addBot(m.room, name, url, false)
+	}
+	log.Printf("[%s] matched %s for a %s game", m.room, strings.Join(players, ", "), m.mode)
+
+	for _, t := range m.tickets {
+		t.found <- &pb.MatchUpdate{
+			WaitedSeconds: int64(time.Since(t.queued) / time.Second),
+			Room:          m.room,
+			Players:       players,
+		}
+	}
+}
+
+// setRoomConfig sets the rules a room will start with, and who can join it if
+// roster isn't nil, if it hasn't opened yet. If nobody joins in time, the rules
+// are forgotten.
+func (s *server) setRoomConfig(room string, cfg *pb.RoomConfig, roster map[string]bool) {
+	s.Lock()
+	s.roomConfigs[room] = cfg
+	s.Unlock()
+	if roster != nil {
+		s.restrictRoom(room, roster)
+	}
+
+	time.AfterFunc(*joinTimeout, func() {
+		s.Lock()
+		defer s.Unlock()
+		if s.roomConfigs[room] == cfg {
+			delete(s.roomConfigs, room)
+			delete(s.roomRosters, room)
+		}
+	})
+}
+
+// restrictRoom only lets the players on the roster into a room, kicking anyone
+// else who's already there.
+func (s *server) restrictRoom(room string, roster map[string]bool) {
+	s.Lock()
+	defer s.Unlock()
+	r, ok := s.rooms[room]
+	if !ok {
+		s.roomRosters[room] = roster
+		return
+	}
+	r.roster = roster
+	r.Lock()
+	defer r.Unlock()
+	for _, snek := range r.sneks {
+		if !roster[snek.name] {
+			snek.out.kick(grpc.Errorf(codes.PermissionDenied, "room %q is only for the players matched into it", room))
+		}
+	}
+}
+
+func (s *server) FindMatch(req *pb.MatchRequest, stream pb.Snek_FindMatchServer) error {
+	ctx := stream.Context()
+	// Ranked matches move ratings, so players have to prove who they are rather
+	// than just pick a name
+	name := s.auth.verifiedName(ctx)
+	if name == "" {
+		return grpc.Errorf(codes.Unauthenticated, "matchmaking needs a token or password to find you a fair match")
+	}
+	if s.isDraining() {
+		return grpc.Errorf(codes.Unavailable, "the server is shutting down")
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = "classic"
+	}
+	if _, err := modeConfig(mode); err != nil {
+		return err
+	}
+	size := int(req.Size)
+	if size == 0 {
+		size = 2
+	}
+	if size < 2 || size > maxMatchSize {
+		return grpc.Errorf(codes.InvalidArgument, "matches have between 2 and %d players", maxMatchSize)
+	}
+
+	t := &ticket{
+		name:   name,
+		rating: s.ratings.rating(name),
+		mode:   mode,
+		size:   size,
+		queued: time.Now(),
+		found:  make(chan *pb.MatchUpdate, 1),
+	}
+	if err := s.matchmaker.enqueue(t); err != nil {
+		return err
+	}
+	defer s.matchmaker.dequeue(t)
+
+	tk := time.NewTicker(*matchInterval)
+	defer tk.Stop()
+	for {
+		select {
+		case m := <-t.found:
+			return stream.Send(m)
+		case <-tk.C:
+			if err := stream.Send(s.matchmaker.status(t)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var metricsAddr = flag.String("metrics_addr", "", "an address to serve Prometheus metrics on at /metrics, e.g. ':9100', empty to disable")
+
+// How a snek's game ended.
+const (
+	outcomeDied   = "died"
+	outcomeLeft   = "left"
+	outcomeKicked = "kicked"
+)
+
+// Upper bounds of the tick duration histogram buckets, in seconds.
+var tickBuckets = []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1}
+
+// serverMetrics are the counters we keep as the server runs. Gauges, like how
+// many sneks are connected, are read from the server when we're scraped.
+type serverMetrics struct {
+	received   int64
+	sent       int64
+	sendErrors int64
+
+	mu       sync.Mutex
+	outcomes map[string]int64
+	// Counts of ticks that took at most the corresponding tickBuckets duration
+	tickCounts []int64
+	tickCount  int64
+	tickSum    float64
+}
+
+var metrics = &serverMetrics{
+	outcomes:   make(map[string]int64),
+	tickCounts: make([]int64, len(tickBuckets)),
+}
+
+func (m *serverMetrics) addReceived() { atomic.AddInt64(&m.received, 1) }
+func (m *serverMetrics) addSent()     { atomic.AddInt64(&m.sent, 1) }
+
+// addErrors counts the errors we had sending out updates, unpacking an
+// updateErr into each of its individual errors.
+func (m *serverMetrics) addErrors(err error) {
+	if err == nil {
+		return
+	}
+	n := int64(1)
+	if u, ok := err.(updateErr); ok {
+		n = int64(len(u))
+	}
+	atomic.AddInt64(&m.sendErrors, n)
+}
+
+func (m *serverMetrics) addOutcome(outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.outcomes[outcome]++
+}
+
+func (m *serverMetrics) observeTick(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secs := d.Seconds()
+	for i, b := range tickBuckets {
+		if secs <= b {
+			m.tickCounts[i]++
+		}
+	}
+	m.tickCount++
+	m.tickSum += secs
+}
+
+// writeMetrics writes out everything in the Prometheus text format.
+func (s *server) writeMetrics(w io.Writer) {
+	s.Lock()
+	rooms, players := len(s.rooms), 0
+	for _, r := range s.rooms {
+		r.Lock()
+		players += len(r.sneks)
+		r.Unlock()
+	}
+	s.Unlock()
+	qs := s.queueStats()
+
+	gauge(w, "snek_rooms", "Rooms with at least one snek in them.", int64(rooms))
+	gauge(w, "snek_players", "Sneks connected to the server.", int64(players))
+	gauge(w, "snek_queued_updates", "Updates waiting to be sent to sneks.", int64(qs.depth))
+	gauge(w, "snek_max_queue_depth", "The most updates any connected snek has had waiting at once.", int64(qs.maxDepth))
+	counter(w, "snek_dropped_updates_total", "Updates dropped because a connected snek's queue was full.", qs.dropped)
+	counter(w, "snek_messages_received_total", "Moves received from sneks.", atomic.LoadInt64(&metrics.received))
+	counter(w, "snek_messages_sent_total", "Updates sent to sneks.", atomic.LoadInt64(&metrics.sent))
+	counter(w, "snek_send_errors_total", "Errors queueing updates for sneks.", atomic.LoadInt64(&metrics.sendErrors))
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP snek_games_total Games that have ended, by how they ended.")
+	fmt.Fprintln(w, "# TYPE snek_games_total counter")
+	var outcomes []string
+	for o := range metrics.outcomes {
+		outcomes = append(outcomes, o)
+	}
+	sort.Strings(outcomes)
+	for _, o := range outcomes {
+		fmt.Fprintf(w, "snek_games_total{outcome=%q} %d\n", o, metrics.outcomes[o])
+	}
+
+	fmt.Fprintln(w, "# HELP snek_tick_duration_seconds How long it takes a room to advance its clock.")
+	fmt.Fprintln(w, "# TYPE snek_tick_duration_seconds histogram")
+	for i, b := range tickBuckets {
+		fmt.Fprintf(w, "snek_tick_duration_seconds_bucket{le=\"%g\"} %d\n", b, metrics.tickCounts[i])
+	}
+	fmt.Fprintf(w, "snek_tick_duration_seconds_bucket{le=\"+Inf\"} %d\n", metrics.tickCount)
+	fmt.Fprintf(w, "snek_tick_duration_seconds_sum %g\n", metrics.tickSum)
+	fmt.Fprintf(w, "snek_tick_duration_seconds_count %d\n", metrics.tickCount)
+}
+
+func gauge(w io.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %d\n", name, help, name, name, v)
+}
+
+func counter(w io.Writer, name, help string, v int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+}
+
+func (s *server) serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		s.writeMetrics(w)
+	})
+	log.Printf("Serving metrics on http://%s/metrics", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("failed to serve metrics: %v", err)
+	}
+}
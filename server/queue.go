@@ -0,0 +1,159 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	queueSize     = flag.Int("queue_size", 64, "how many updates to buffer for each snek before it's considered slow")
+	slowPolicy    = flag.String("slow_policy", "drop", "what to do when a snek's queue is full, either 'drop' the update or 'kick' the snek")
+	maxDrops      = flag.Int("max_drops", 40, "with the 'drop' policy, how many updates in a row a snek can miss before it gets kicked anyway")
+	statsInterval = flag.Duration("stats_interval", time.Minute, "how often to log queue stats, 0 to disable")
+)
+
+// outQueue is a bounded buffer of updates waiting to be sent to a snek. Sends
+// never block, so one slow connection can't hold up everyone else.
+type outQueue struct {
+	updates chan *pb.UpdateResponse
+
+	// How many updates in a row we've had to drop
+	drops int
+	// The most updates we've had waiting at once
+	maxDepth int
+	// Total updates we've dropped
+	dropped int64
+
+	kickOnce sync.Once
+	// Closed when we've given up on the snek
+	kicked chan struct{}
+	// Why we gave up on them
+	kickErr error
+
+	closeOnce sync.Once
+	// Closed when we want to hang up on the snek once the queue is empty
+	closing chan struct{}
+}
+
+func newOutQueue(size int) *outQueue {
+	return &outQueue{
+		updates: make(chan *pb.UpdateResponse, size),
+		kicked:  make(chan struct{}),
+		closing: make(chan struct{}),
+	}
+}
+
+func (q *outQueue) kick(reason error) {
+	q.kickOnce.Do(func() {
+		q.kickErr = reason
+		close(q.kicked)
+	})
+}
+
+func (q *outQueue) close() {
+	q.closeOnce.Do(func() { close(q.closing) })
+}
+
+// push queues up an update without blocking, and must be called with the
+// snek's room locked. If the queue is full, the update is dropped, and depending on
+// the policy the snek may get kicked. Only the first drop in a row is reported
+// as an error, so a lagging snek doesn't flood the logs.
+func (q *outQueue) push(id ID, resp *pb.UpdateResponse) error {
+	select {
+	case q.updates <- resp:
+		q.drops = 0
+		if d := len(q.updates); d > q.maxDepth {
+			q.maxDepth = d
+		}
+		return nil
+	default:
+	}
+
+	q.dropped++
+	q.drops++
+	if *slowPolicy == "kick" || q.drops > *maxDrops {
+		q.kick(grpc.Errorf(codes.ResourceExhausted, "you fell too far behind"))
+		return fmt.Errorf("snek %d is too slow, kicking it after %d dropped updates", id, q.drops)
+	}
+	if q.drops == 1 {
+		return fmt.Errorf("snek %d is falling behind, dropping updates", id)
+	}
+	return nil
+}
+
+// drain sends queued updates to the snek until the stream is done, the snek is
+// kicked, or the queue is closed and has been emptied.
+func (s *snek) drain() error {
+	for {
+		select {
+		case resp := <-s.out.updates:
+			if err := s.stream.Send(resp); err != nil {
+				return err
+			}
+			metrics.addSent()
+		case <-s.out.kicked:
+			return s.out.kickErr
+		case <-s.out.closing:
+			return s.flush()
+		case <-s.stream.Context().Done():
+			return s.stream.Context().Err()
+		}
+	}
+}
+
+// flush sends whatever is left in the queue, without waiting for more.
+func (s *snek) flush() error {
+	for {
+		select {
+		case resp := <-s.out.updates:
+			if err := s.stream.Send(resp); err != nil {
+				return err
+			}
+			metrics.addSent()
+		default:
+			return nil
+		}
+	}
+}
+
+type queueStats struct {
+	sneks    int
+	depth    int
+	maxDepth int
+	dropped  int64
+}
+
+func (s *server) queueStats() queueStats {
+	s.Lock()
+	defer s.Unlock()
+	var st queueStats
+	for _, r := range s.rooms {
+		r.Lock()
+		for _, snek := range r.sneks {
+			st.sneks++
+			st.depth += len(snek.out.updates)
+			if snek.out.maxDepth > st.maxDepth {
+				st.maxDepth = snek.out.maxDepth
+			}
+			st.dropped += snek.out.dropped
+		}
+		r.Unlock()
+	}
+	return st
+}
+
+func (s *server) logStats(d time.Duration) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for range t.C {
+		st := s.queueStats()
+		log.Printf("%d sneks, %d updates queued, max queue depth %d, %d updates dropped", st.sneks, st.depth, st.maxDepth, st.dropped)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	profilesFile = flag.String("profiles", "", "a file to keep player profiles and ratings in, empty to only keep them until the server restarts")
+	eloK         = flag.Float64("elo_k", 32, "the most a player's rating can change from a single result")
+)
+
+const (
+	startRating = 1500
+	// How many results we keep in a player's history
+	maxHistory = 50
+)
+
+// ratings are Elo ratings for every named player, updated whenever a snek dies
+// in a room with other named players in it. The snek that died loses to every
+// other player still alive in the room, with its rating change split between
+// them. Only players who proved who they are with a token or password, and
+// the server's own bots, are rated, since anyone can call themselves anything.
+type ratings struct {
+	sync.Mutex
+	file     string
+	profiles map[string]*pb.PlayerProfile
+	// Signals the saver that there's something new to write out
+	dirty chan struct{}
+}
+
+func loadRatings(file string) (*ratings, error) {
+	rt := &ratings{
+		file:     file,
+		profiles: make(map[string]*pb.PlayerProfile),
+		dirty:    make(chan struct{}, 1),
+	}
+	if file == "" {
+		return rt, nil
+	}
+
+	buf, err := ioutil.ReadFile(file)
+	switch {
+	case os.IsNotExist(err):
+		// We'll make it the first time someone's rated
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(buf, &rt.profiles); err != nil {
+			return nil, err
+		}
+	}
+	go rt.saveChanges()
+	return rt, nil
+}
+
+// profile returns the named player's profile, creating it if we haven't seen
+// them before. It must be called with the ratings locked.
+func (rt *ratings) profile(name string) *pb.PlayerProfile {
+	p, ok := rt.profiles[name]
+	if !ok {
+		p = &pb.PlayerProfile{Name: name, Rating: startRating}
+		rt.profiles[name] = p
+	}
+	return p
+}
+
+// rating returns the named player's rating, without making them a profile.
+func (rt *ratings) rating(name string) float64 {
+	rt.Lock()
+	defer rt.Unlock()
+	if p, ok := rt.profiles[name]; ok {
+		return p.Rating
+	}
+	return startRating
+}
+
+// recordDeath rates a snek dying while the others were still alive.
+func (rt *ratings) recordDeath(room, loser string, survivors []string) {
+	if loser == "" || len(survivors) == 0 {
+		return
+	}
+	rt.Lock()
+	defer rt.Unlock()
+
+	l := rt.profile(loser)
+	k := *eloK / float64(len(survivors))
+	now := time.Now().Unix()
+	var total float64
+	for _, name := range survivors {
+		w := rt.profile(name)
+		// How likely the loser was to win, by the ratings before this game
+		expected := 1 / (1 + math.Pow(10, (w.Rating-l.Rating)/400))
+		d := k * expected
+		w.Rating += d
+		w.Wins++
+		addHistory(w, &pb.MatchRecord{TimeUnix: now, Room: room, Opponent: loser, Won: true, RatingChange: d})
+		addHistory(l, &pb.MatchRecord{TimeUnix: now, Room: room, Opponent: name, RatingChange: -d})
+		total += d
+	}
+	l.Rating -= total
+	l.Losses++
+
+	select {
+	case rt.dirty <- struct{}{}:
+	default:
+	}
+}
+
+func addHistory(p *pb.PlayerProfile, rec *pb.MatchRecord) {
+	p.History = append([]*pb.MatchRecord{rec}, p.History...)
+	if len(p.History) > maxHistory {
+		p.History = p.History[:maxHistory]
+	}
+}
+
+// ranked returns copies of every profile, best first, with their ranks filled
+// in.
+func (rt *ratings) ranked() []*pb.PlayerProfile {
+	rt.Lock()
+	defer rt.Unlock()
+	var all []*pb.PlayerProfile
+	for _, p := range rt.profiles {
+		all = append(all, proto.Clone(p).(*pb.PlayerProfile))
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Rating != all[j].Rating {
+			return all[i].Rating > all[j].Rating
+		}
+		return all[i].Name < all[j].Name
+	})
+	for i, p := range all {
+		p.Rank = int32(i + 1)
+	}
+	return all
+}
+
+// saveChanges writes the profiles out whenever they change, so the rooms never
+// have to wait on the disk.
+func (rt *ratings) saveChanges() {
+	for range rt.dirty {
+		if err := rt.save(); err != nil {
+			log.Printf("failed to save profiles to %s: %v", rt.file, err)
+		}
+	}
+}
+
+func (rt *ratings) save() error {
+	rt.Lock()
+	buf, err := json.MarshalIndent(rt.profiles, "", "  ")
+	rt.Unlock()
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a crash halfway through doesn't lose
+	// everyone's profile
+	if err := ioutil.WriteFile(rt.file+".tmp", buf, 0644); err != nil {
+		return err
+	}
+	return os.Rename(rt.file+".tmp", rt.file)
+}
+
+func (s *server) Leaderboard(ctx context.Context, req *pb.LeaderboardRequest) (*pb.LeaderboardResponse, error) {
+	all := s.ratings.ranked()
+	if req.Limit > 0 && int(req.Limit) < len(all) {
+		all = all[:req.Limit]
+	}
+	for _, p := range all {
+		p.History = nil
+	}
+	return &pb.LeaderboardResponse{Players: all}, nil
+}
+
+func (s *server) Profile(ctx context.Context, req *pb.ProfileRequest) (*pb.PlayerProfile, error) {
+	for _, p := range s.ratings.ranked() {
+		if p.Name == req.Name {
+			return p, nil
+		}
+	}
+	return nil, grpc.Errorf(codes.NotFound, "no profile for %q", req.Name)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const defaultRoom = "arena"
+
+// room is a single game, with its own set of sneks and its own clock.
+type room struct {
+	sync.Mutex
+	name   string
+	sneks  map[ID]*snek
+	config *pb.RoomConfig
+	// The current tick of the game clock, everyone moves in lockstep with it
+	tick int64
+	// When the clock last ticked, so we can tell if it's stuck
+	lastTick time.Time
+	// Closed to stop the clock
+	stop chan struct{}
+	// Sends the clock a new tick rate
+	retick  chan time.Duration
+	ratings *ratings
+	royale  royale
+	// The teams as we last told everyone about them
+	lastTeams []*pb.Team
+	// Set once the game's been ended, so leaving it doesn't count as losing
+	ended bool
+	// The only players allowed in, if it's set. It's guarded by the server's
+	// lock, not the room's.
+	roster map[string]bool
+}
+
+func newRoom(name string, ratings *ratings) *room {
+	return &room{
+		name:    name,
+		sneks:   make(map[ID]*snek),
+		ratings: ratings,
+		config: &pb.RoomConfig{
+			TickMillis: int64(*tickRate / time.Millisecond),
+			Wrap:       *wrap,
+		},
+		lastTick: time.Now(),
+		stop:     make(chan struct{}),
+		retick:   make(chan time.Duration),
+	}
+}
+
+func (r *room) tickRate() time.Duration {
+	return time.Duration(r.config.TickMillis) * time.Millisecond
+}
+
+// add puts a snek in the room and tells it the room's rules.
+func (r *room) add(snek *snek) {
+	r.Lock()
+	defer r.Unlock()
+	r.sneks[snek.id] = snek
+	resp := &pb.UpdateResponse{Config: r.config, You: int32(snek.id)}
+	if r.teamGame() {
+		r.pickTeam(snek)
+		spawn := r.spawn(snek)
+		snek.moves.spawn, snek.moves.assigned = Loc{spawn.X, spawn.Y}, true
+		resp.Spawn = &pb.Loc{X: int32(spawn.X), Y: int32(spawn.Y)}
+	}
+	if err := snek.send(resp); err != nil {
+		log.Printf("[%s] add(%d): %v", r.name, snek.id, err)
+	}
+	if err := r.updateTeams(); err != nil {
+		metrics.addErrors(err)
+		log.Printf("[%s] add(%d): %v", r.name, snek.id, err)
+	}
+}
+
+// configure changes the rules for the room, and returns how many sneks were
+// told about it.
+func (r *room) configure(cfg *pb.RoomConfig) int {
+	r.Lock()
+	old := r.tickRate()
+	if cfg.Mode != r.config.Mode {
+		// Changing modes starts a new game
+		r.royale = royale{}
+		r.lastTeams = nil
+	}
+	r.config = cfg
+	if r.teamGame() {
+		r.balanceTeams()
+	}
+	r.closeIn()
+	d := r.tickRate()
+	if err := r.broadcast(&pb.UpdateResponse{Config: cfg}); err != nil {
+		metrics.addErrors(err)
+		log.Printf("[%s] configure(%v): %v", r.name, cfg, err)
+	}
+	n := len(r.sneks)
+	r.Unlock()
+
+	// The clock takes the room lock to tick, so we can't be holding it here
+	if d != old {
+		select {
+		case r.retick <- d:
+		case <-r.stop:
+		}
+	}
+	return n
+}
+
+// announce shows a notice to everyone in the room, and returns how many sneks
+// saw it.
+func (r *room) announce(notice string) int {
+	r.Lock()
+	defer r.Unlock()
+	if err := r.broadcast(&pb.UpdateResponse{Notice: notice}); err != nil {
+		metrics.addErrors(err)
+		log.Printf("[%s] announce(%q): %v", r.name, notice, err)
+	}
+	return len(r.sneks)
+}
+
+// end tells everyone in the room why the game is over, and hangs up on them
+// once they've been sent everything in their queue. It returns how many sneks
+// were in the room.
+func (r *room) end(notice string) int {
+	r.Lock()
+	defer r.Unlock()
+	r.finish(notice)
+	return len(r.sneks)
+}
+
+// finish is end for when the room is already locked.
+func (r *room) finish(notice string) {
+	r.ended = true
+	if err := r.broadcast(&pb.UpdateResponse{Notice: notice}); err != nil {
+		metrics.addErrors(err)
+		log.Printf("[%s] end(%q): %v", r.name, notice, err)
+	}
+	for _, snek := range r.sneks {
+		snek.out.close()
+	}
+}
+
+// remove returns how many sneks are left in the room, and whether the one that
+// left had died. Leaving a game you've started playing and haven't died in
+// counts as losing it, so you can't keep your rating by quitting.
+func (r *room) remove(snek *snek) (int, bool) {
+	r.Lock()
+	defer r.Unlock()
+	if !snek.moves.dead && !r.ended && len(snek.body.Body) > 0 {
+		r.ratings.recordDeath(r.name, snek.ratedName(), r.survivors(snek))
+	}
+	delete(r.sneks, snek.id)
+	return len(r.sneks), snek.moves.dead
+}
+
+// runClock advances the game clock until the room is closed, telling every
+// snek when it's time to make their next move. Updates with an ID of 0 come
+// from the server itself.
+func (r *room) runClock(d time.Duration) {
+	t := time.NewTicker(d)
+	defer func() { t.Stop() }()
+	for {
+		select {
+		case d := <-r.retick:
+			t.Stop()
+			t = time.NewTicker(d)
+		case <-t.C:
+			start := time.Now()
+			err := r.advance()
+			metrics.observeTick(time.Since(start))
+			if err != nil {
+				metrics.addErrors(err)
+				log.Printf("[%s] advance(): %v", r.name, err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *room) advance() error {
+	r.Lock()
+	defer r.Unlock()
+	r.tick++
+	r.lastTick = time.Now()
+	// Keep ticking once a battle royale's over, so nobody who's still here is
+	// left waiting for a tick that never comes
+	r.advanceRoyale()
+	if err := r.updateTeams(); err != nil {
+		return err
+	}
+	return r.broadcast(&pb.UpdateResponse{Tick: r.tick, Margin: int32(r.board().Margin)})
+}
+
+// broadcast sends an update to every snek in the room, and must be called with
+// the room locked.
+func (r *room) broadcast(resp *pb.UpdateResponse) error {
+	var errs updateErr
+	for _, snek := range r.sneks {
+		if err := snek.send(resp); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// stamp returns the tick a move should be recorded on. Moves are made in
+// response to a tick, so we keep the client's tick unless it's claiming to be
+// from a tick that hasn't happened yet.
+func (r *room) stamp(tick int64) int64 {
+	if tick <= 0 || tick > r.tick {
+		return r.tick
+	}
+	return tick
+}
+
+// move checks that a snek's move is legal and then sends it out to everyone in
+// the room. Illegal moves aren't sent anywhere, and sneks that keep making them
+// get kicked.
+func (r *room) move(req *pb.UpdateRequest, from *snek) error {
+	r.Lock()
+	defer r.Unlock()
+	tick := r.stamp(req.Tick)
+	if req.Dead {
+		if from.moves.dead {
+			// We already know, the room must have killed it
+			return nil
+		}
+		return r.kill(from, tick)
+	}
+	if err := from.moves.check(req, tick, r.config.Wrap, from.body.Body); err != nil {
+		from.moves.violations++
+		if *maxViolations > 0 && from.moves.violations >= *maxViolations {
+			from.out.kick(grpc.Errorf(codes.PermissionDenied, "too many illegal moves"))
+			return fmt.Errorf("kicked snek %d after %d illegal moves, the last was: %v", from.id, from.moves.violations, err)
+		}
+		from.send(&pb.UpdateResponse{Notice: "Illegal move: " + err.Error()})
+		return fmt.Errorf("illegal move from snek %d: %v", from.id, err)
+	}
+	from.body.Follow(ruleLoc(req.NewHead), ruleLoc(req.OldTail))
+	if !from.body.Within(r.board()) {
+		from.send(&pb.UpdateResponse{Notice: "You left the arena"})
+		return r.kill(from, tick)
+	}
+	if r.teamGame() {
+		if into := r.collision(from); into != nil {
+			return r.crash(from, into, tick)
+		}
+	}
+	err := r.sendUpdates(req, from.id, tick)
+	metrics.addErrors(err)
+	return err
+}
+
+// kill marks a snek as dead and lets everyone know, so they can clear it off
+// the board. It must be called with the room locked.
+func (r *room) kill(snek *snek, tick int64) error {
+	snek.moves.dead = true
+	r.ratings.recordDeath(r.name, snek.ratedName(), r.survivors(snek))
+	err := r.broadcast(&pb.UpdateResponse{Id: int32(snek.id), Tick: tick, Dead: true})
+	metrics.addErrors(err)
+	return err
+}
+
+// survivors returns the names of everyone rated still alive in the room, other
+// than the given snek and its teammates, and must be called with the room
+// locked.
+func (r *room) survivors(than *snek) []string {
+	seen := map[string]bool{than.name: true}
+	var names []string
+	for _, snek := range r.sneks {
+		if snek.ratedName() != "" && !snek.moves.dead && !seen[snek.name] && !r.teammates(snek, than) {
+			seen[snek.name] = true
+			names = append(names, snek.name)
+		}
+	}
+	return names
+}
+
+// sendUpdates must be called with the room locked.
+func (r *room) sendUpdates(req *pb.UpdateRequest, id ID, tick int64) error {
+	var errs updateErr
+	resp := &pb.UpdateResponse{
+		Id:      int32(id),
+		NewHead: req.NewHead,
+		OldTail: req.OldTail,
+		Tick:    tick,
+	}
+	for _, snek := range r.sneks {
+		u := resp
+		if snek.id == id {
+			// Echo the move back to the sender so they can check it against what
+			// they predicted and measure their latency
+			ack := *resp
+			ack.Ack = true
+			u = &ack
+		}
+		if err := snek.send(u); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
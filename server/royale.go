@@ -0,0 +1,123 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+var shrinkTicks = flag.Int64("shrink_ticks", 100, "how many ticks it takes a battle royale arena to close in by a cell, unless the room's config says otherwise")
+
+// The smallest a battle royale arena gets, so there's always room for food
+const minArena = 6
+
+// The furthest an arena can close in from each edge
+var maxMargin = (min(pb.BoardWidth, pb.BoardHeight) - minArena) / 2
+
+// royale is how a battle royale is going.
+type royale struct {
+	// Set once there have been two sneks in the room at once, which starts the
+	// arena closing in
+	started bool
+	// The tick it started on
+	start int64
+	// How many cells the arena has closed in from each edge
+	margin int
+	// Set once there's one snek left
+	over bool
+}
+
+// board returns the board as it stands in the room, with the arena closed in
+// as far as it's gotten, or as far as the room's config says.
+func (r *room) board() rules.Board {
+	b := rules.NewBoard(r.config.Wrap)
+	b.Margin = r.royale.margin
+	if m := int(r.config.Margin); m > b.Margin {
+		b.Margin = m
+	}
+	return b
+}
+
+// advanceRoyale moves a battle royale along a tick, killing anyone the arena
+// closes in on, and ends it once there's one snek left. It must be called with
+// the room locked.
+func (r *room) advanceRoyale() {
+	if r.config.Mode != pb.RoomMode_BATTLE_ROYALE || r.royale.over {
+		return
+	}
+	rs := &r.royale
+	alive := r.alive()
+	if !rs.started {
+		if len(alive) < 2 {
+			return
+		}
+		rs.started, rs.start = true, r.tick
+		r.broadcast(&pb.UpdateResponse{Notice: fmt.Sprintf("Battle royale! The arena closes in every %d ticks", r.shrinkTicks())})
+	}
+
+	m := int(r.config.Margin) + int((r.tick-rs.start)/r.shrinkTicks())
+	if m > maxMargin {
+		m = maxMargin
+	}
+	if m != rs.margin {
+		rs.margin = m
+		r.closeIn()
+		alive = r.alive()
+	}
+
+	if len(alive) <= 1 {
+		rs.over = true
+		notice := "Nobody survived the battle royale"
+		if len(alive) == 1 {
+			notice = fmt.Sprintf("%s wins the battle royale", alive[0].displayName())
+		}
+		r.finish(notice)
+	}
+}
+
+// closeIn kills every snek the arena has closed in on. It must be called with
+// the room locked.
+func (r *room) closeIn() {
+	b := r.board()
+	for _, snek := range r.alive() {
+		if !snek.body.Within(b) {
+			snek.send(&pb.UpdateResponse{Notice: "The arena closed in on you"})
+			r.kill(snek, r.tick)
+		}
+	}
+}
+
+// royaleOver returns whether the room's battle royale has finished.
+func (r *room) royaleOver() bool {
+	r.Lock()
+	defer r.Unlock()
+	return r.royale.over
+}
+
+func (r *room) shrinkTicks() int64 {
+	if r.config.ShrinkTicks > 0 {
+		return r.config.ShrinkTicks
+	}
+	return *shrinkTicks
+}
+
+// alive returns every snek in the room that hasn't died, and must be called
+// with the room locked.
+func (r *room) alive() []*snek {
+	var sneks []*snek
+	for _, snek := range r.sneks {
+		if !snek.moves.dead {
+			sneks = append(sneks, snek)
+		}
+	}
+	return sneks
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
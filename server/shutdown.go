@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// shutdown tells every snek that we're going away, and hangs up on them once
+// they've been sent everything still in their queue. No new sneks can join
+// after this.
+func (s *server) shutdown(notice string) {
+	s.Lock()
+	defer s.Unlock()
+	s.draining = true
+	s.health.Shutdown()
+	for _, r := range s.rooms {
+		r.end(notice)
+	}
+}
+
+// stopOnSignal shuts the server down cleanly on SIGINT or SIGTERM, forcing it
+// closed if the sneks take longer than the timeout to go away. The returned
+// channel is closed once the server has stopped.
+func stopOnSignal(srv *server, grpcServer *grpc.Server, timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer close(done)
+		sig := <-sigs
+		log.Printf("Got %v, shutting down", sig)
+		srv.shutdown("The server is shutting down")
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(timeout):
+			log.Printf("Sneks didn't disconnect after %s, stopping anyway", timeout)
+			grpcServer.Stop()
+		}
+	}()
+	return done
+}
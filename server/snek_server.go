@@ -2,13 +2,33 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"flag"
 	"io"
 	"log"
 	"net"
 	"sync"
+	"time"
 
 	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+)
+
+var (
+	addr            = flag.String("addr", ":6000", "the address to listen on")
+	tlsCert         = flag.String("tls_cert", "", "a certificate file to serve TLS with, requires -tls_key")
+	tlsKey          = flag.String("tls_key", "", "the private key for -tls_cert")
+	tickRate        = flag.Duration("tick", 75*time.Millisecond, "how often the sneks move in new rooms")
+	wrap            = flag.Bool("wrap", false, "whether sneks wrap around the board in new rooms")
+	shutdownTimeout = flag.Duration("shutdown_timeout", 5*time.Second, "how long to wait for sneks to disconnect when shutting down")
 )
 
 type updateErr []error
@@ -24,96 +44,316 @@ func (u updateErr) Error() string {
 	return buf.String()
 }
 
+// updateStream is how we talk to a snek, either over gRPC or a WebSocket.
+type updateStream interface {
+	Send(*pb.UpdateResponse) error
+	Recv() (*pb.UpdateRequest, error)
+	Context() context.Context
+}
+
 type ID int64
 type snek struct {
-	id     ID
-	stream pb.Snek_UpdateServer
+	id   ID
+	name string
+	// Whether the snek's name is verified, so its games count towards its
+	// rating
+	rated bool
+	// The host the snek is connecting from
+	host   string
+	room   *room
+	stream updateStream
+	out    *outQueue
+	moves  moveLog
+	// The snek's body as far as we can tell from its moves
+	body rules.Snek
+	// Which team the snek is on in a team game, starting from 1
+	team int
+	// How many sneks on other teams have run into this one
+	kills int
+}
+
+// ratedName returns the snek's name if its games are rated, or an empty string
+// if they aren't.
+func (s *snek) ratedName() string {
+	if !s.rated {
+		return ""
+	}
+	return s.name
 }
 
 func (s *snek) send(resp *pb.UpdateResponse) error {
-	return s.stream.Send(resp)
+	return s.out.push(s.id, resp)
+}
+
+func (s *snek) displayName() string {
+	if s.name == "" {
+		return "anonymous"
+	}
+	return s.name
 }
 
 type server struct {
 	sync.Mutex
-	sneks     map[ID]*snek
+	rooms     map[string]*room
 	highestID ID
+	auth      *authenticator
+	// Set once we've started shutting down
+	draining bool
+	health   *health.Server
+	// Names and hosts that aren't allowed to join, and why
+	bannedNames map[string]string
+	bannedHosts map[string]string
+	tournaments map[string]*tournament
+	ratings     *ratings
+	daily       *dailies
+	matchmaker  *matchmaker
+	// The rules for rooms that have been set up but haven't opened yet, and who
+	// can join the ones only some players are allowed in
+	roomConfigs map[string]*pb.RoomConfig
+	roomRosters map[string]map[string]bool
 }
 
-func newServer() *server {
+func newServer(auth *authenticator, ratings *ratings, daily *dailies) *server {
 	return &server{
-		sneks: make(map[ID]*snek),
+		rooms:       make(map[string]*room),
+		auth:        auth,
+		ratings:     ratings,
+		daily:       daily,
+		health:      health.NewServer(),
+		bannedNames: make(map[string]string),
+		bannedHosts: make(map[string]string),
+		tournaments: make(map[string]*tournament),
+		matchmaker:  &matchmaker{},
+		roomConfigs: make(map[string]*pb.RoomConfig),
+		roomRosters: make(map[string]map[string]bool),
 	}
 }
 
-func (s *server) removeSnek(snek *snek) {
+// removeSnek takes a snek out of its room, and closes the room if it was the
+// last one there. It returns whether the snek had died before it left.
+func (s *server) removeSnek(snek *snek) bool {
 	s.Lock()
 	defer s.Unlock()
-	delete(s.sneks, snek.id)
+	r := snek.room
+	left, died := r.remove(snek)
+	if left == 0 {
+		close(r.stop)
+		delete(s.rooms, r.name)
+	}
+	return died
 }
 
-func (s *server) addSnek(stream pb.Snek_UpdateServer) *snek {
+// addSnek puts a new snek in the named room, opening the room if it doesn't
+// exist yet.
+func (s *server) addSnek(stream updateStream, roomName string, id *identity, host string) (*snek, error) {
 	s.Lock()
 	defer s.Unlock()
-	id := s.highestID + 1
-	s.highestID = id
-	snek := &snek{id: id, stream: stream}
-	s.sneks[id] = snek
-	return snek
-}
+	if s.draining {
+		return nil, grpc.Errorf(codes.Unavailable, "the server is shutting down")
+	}
+	if reason, ok := s.bannedHosts[host]; ok {
+		return nil, grpc.Errorf(codes.PermissionDenied, "you're banned: %s", reason)
+	}
+	if reason, ok := s.bannedNames[id.name]; ok && id.name != "" {
+		return nil, grpc.Errorf(codes.PermissionDenied, "you're banned: %s", reason)
+	}
 
-func (s *server) sendUpdates(req *pb.UpdateRequest, id ID) error {
-	s.Lock()
-	var errs updateErr
-	defer s.Unlock()
-	resp := &pb.UpdateResponse{
-		Id:      int32(id),
-		NewHead: req.NewHead,
-		OldTail: req.OldTail,
-	}
-	for _, snek := range s.sneks {
-		if snek.id == id {
-			continue
-		}
-		if err := snek.send(resp); err != nil {
-			errs = append(errs, err)
+	r, ok := s.rooms[roomName]
+	if ok && r.royaleOver() {
+		// Everyone's being sent home, the room closes once they've gone
+		return nil, grpc.Errorf(codes.FailedPrecondition, "the battle royale in room %q is over, try again in a moment", roomName)
+	}
+	roster := s.roomRosters[roomName]
+	if ok {
+		roster = r.roster
+	}
+	if roster != nil && !roster[id.name] {
+		return nil, grpc.Errorf(codes.PermissionDenied, "room %q is only for the players matched into it", roomName)
+	}
+	if !ok {
+		r = newRoom(roomName, s.ratings)
+		if cfg, ok := s.roomConfigs[roomName]; ok {
+			r.config = cfg
+			delete(s.roomConfigs, roomName)
 		}
+		r.roster = roster
+		delete(s.roomRosters, roomName)
+		s.rooms[roomName] = r
+		go r.runClock(r.tickRate())
 	}
-	if len(errs) != 0 {
-		return errs
+	s.highestID++
+	snek := &snek{
+		id:     s.highestID,
+		name:   id.name,
+		rated:  id.verified,
+		host:   host,
+		room:   r,
+		stream: stream,
+		out:    newOutQueue(*queueSize),
 	}
-	return nil
+	r.add(snek)
+	return snek, nil
+}
+
+// peerHost returns the host a request came from, without the port.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
 }
 
 func (s *server) Update(stream pb.Snek_UpdateServer) error {
+	// Players pick their room, and prove who they are, with request metadata
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	roomName := first(md, "room")
+	if roomName == "" {
+		roomName = defaultRoom
+	}
+	id, err := s.auth.authenticate(stream.Context(), roomName)
+	if err != nil {
+		log.Printf("Rejected snek for room %q: %v", roomName, err)
+		return err
+	}
+	return s.play(stream, roomName, id, peerHost(stream.Context()))
+}
+
+// play adds a snek to a room and relays its moves until it leaves, dies or
+// gets kicked.
+func (s *server) play(stream updateStream, roomName string, id *identity, host string) error {
 	// When we start a stream, we add a new snek to our collection
-	snek := s.addSnek(stream)
-	log.Printf("Started stream for snek %d", snek.id)
-	for {
-		in, err := stream.Recv()
+	snek, err := s.addSnek(stream, roomName, id, host)
+	if err != nil {
+		log.Printf("Rejected snek for room %q: %v", roomName, err)
+		return err
+	}
+	if b, ok := stream.(*botStream); ok {
+		b.joined(snek.id)
+	}
+	log.Printf("Started stream for snek %d (%s) in room %q", snek.id, snek.displayName(), roomName)
+
+	// Reading and writing happen separately, so a snek that's slow to receive
+	// updates only ever holds up itself
+	errs := make(chan error, 2)
+	go func() { errs <- snek.drain() }()
+	go func() { errs <- s.receive(snek) }()
+
+	outcome := outcomeLeft
+	select {
+	case err = <-errs:
+	case <-snek.out.kicked:
+	}
+	// drain stops as soon as we're kicked, so its error can get here at the
+	// same time as the kick. Either way, the kick is why we're done.
+	select {
+	case <-snek.out.kicked:
+		err = snek.out.kickErr
+		outcome = outcomeKicked
+	default:
 		if err == io.EOF {
-			s.removeSnek(snek)
-			return nil
+			err = nil
 		}
+	}
+
+	if s.removeSnek(snek) {
+		outcome = outcomeDied
+	}
+	metrics.addOutcome(outcome)
+	return err
+}
+
+func (s *server) receive(snek *snek) error {
+	for {
+		in, err := snek.stream.Recv()
 		if err != nil {
-			s.removeSnek(snek)
 			return err
 		}
-		if err := s.sendUpdates(in, snek.id); err != nil {
-			log.Printf("sendUpdates(%v, %d): %v", in, snek.id, err)
+		metrics.addReceived()
+		if err := snek.room.move(in, snek); err != nil {
+			log.Printf("[%s] move(%v, %d): %v", snek.room.name, in, snek.id, err)
 		}
 	}
 }
 
 func main() {
-	grpcServer := grpc.NewServer()
-	pb.RegisterSnekServer(grpcServer, newServer())
+	flag.Parse()
+	if *hashPassword {
+		if err := printPasswordHash(); err != nil {
+			log.Fatalf("failed to hash password: %v", err)
+		}
+		return
+	}
+	if *slowPolicy != "drop" && *slowPolicy != "kick" {
+		log.Fatalf("unknown -slow_policy %q, must be 'drop' or 'kick'", *slowPolicy)
+	}
+
+	auth, err := loadAuthenticator(*tokenFile, *userFile, *openRooms)
+	if err != nil {
+		log.Fatalf("failed to load credentials: %v", err)
+	}
 
-	l, err := net.Listen("tcp", ":6000")
+	ratings, err := loadRatings(*profilesFile)
+	if err != nil {
+		log.Fatalf("failed to load profiles: %v", err)
+	}
+
+	daily, err := loadDailies(*dailyFile)
+	if err != nil {
+		log.Fatalf("failed to load daily challenge results: %v", err)
+	}
+
+	bots, err := parseBots(*matchBots)
+	if err != nil {
+		log.Fatalf("failed to parse -match_bots: %v", err)
+	}
+
+	srv := newServer(auth, ratings, daily)
+	if *statsInterval > 0 {
+		go srv.logStats(*statsInterval)
+	}
+	if *metricsAddr != "" {
+		go srv.serveMetrics(*metricsAddr)
+	}
+	if *webAddr != "" {
+		go srv.serveWeb(*webAddr, *tlsCert, *tlsKey)
+	}
+
+	var opts []grpc.ServerOption
+	if *tlsCert != "" || *tlsKey != "" {
+		creds, err := credentials.NewServerTLSFromFile(*tlsCert, *tlsKey)
+		if err != nil {
+			log.Fatalf("failed to load TLS credentials: %v", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	pb.RegisterSnekServer(grpcServer, srv)
+	if *adminToken != "" {
+		pb.RegisterAdminServer(grpcServer, &adminServer{srv: srv, token: *adminToken})
+	}
+	healthpb.RegisterHealthServer(grpcServer, srv.health)
+	reflection.Register(grpcServer)
+	go srv.watchHealth(*healthInterval)
+	go srv.runMatchmaker(*matchInterval, bots)
+
+	l, err := net.Listen("tcp", *addr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	log.Println("Listening on tcp://localhost:6000")
-	grpcServer.Serve(l)
+	stopped := stopOnSignal(srv, grpcServer, *shutdownTimeout)
+	log.Printf("Listening on tcp://%s", l.Addr())
+	if err := srv.startBots(*botList); err != nil {
+		log.Fatalf("failed to start bots: %v", err)
+	}
+	if err := grpcServer.Serve(l); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+	<-stopped
 }
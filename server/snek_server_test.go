@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream is a snek's end of an Update stream that never moves, and keeps
+// whatever the server sends it.
+type fakeStream struct {
+	ctx  context.Context
+	sent chan *pb.UpdateResponse
+}
+
+func newFakeStream(ctx context.Context) *fakeStream {
+	return &fakeStream{ctx: ctx, sent: make(chan *pb.UpdateResponse, 1024)}
+}
+
+func (f *fakeStream) Send(resp *pb.UpdateResponse) error {
+	select {
+	case f.sent <- resp:
+	default:
+	}
+	return nil
+}
+
+func (f *fakeStream) Recv() (*pb.UpdateRequest, error) {
+	<-f.ctx.Done()
+	return nil, f.ctx.Err()
+}
+
+func (f *fakeStream) Context() context.Context {
+	return f.ctx
+}
+
+func newTestServer(t *testing.T) *server {
+	ratings, err := loadRatings("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily, err := loadDailies("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newServer(nil, ratings, daily)
+}
+
+// waitForSneks waits until there are n sneks in a room.
+func waitForSneks(t *testing.T, s *server, room string, n int) {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(time.Millisecond) {
+		if r, err := s.room(room); err == nil {
+			r.Lock()
+			got := len(r.sneks)
+			r.Unlock()
+			if got == n {
+				return
+			}
+		}
+	}
+	t.Fatalf("room %q never had %d sneks in it", room, n)
+}
+
+func TestDrainReturnsKickError(t *testing.T) {
+	s := &snek{stream: newFakeStream(context.Background()), out: newOutQueue(4)}
+	kickErr := status.Errorf(codes.ResourceExhausted, "too slow")
+	s.out.kick(kickErr)
+	if err := s.drain(); err != kickErr {
+		t.Errorf("drain() = %v, want %v", err, kickErr)
+	}
+}
+
+func TestKickedSnekGetsKickError(t *testing.T) {
+	s := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Getting kicked races with the snek's queue giving up on it, so try it a
+	// few times
+	for i := 0; i < 20; i++ {
+		metrics.mu.Lock()
+		kicked := metrics.outcomes[outcomeKicked]
+		metrics.mu.Unlock()
+
+		done := make(chan error, 1)
+		go func() { done <- s.play(newFakeStream(ctx), "kick", &identity{name: "mallory"}, "") }()
+		waitForSneks(t, s, "kick", 1)
+		if n := s.kick(func(*snek) bool { return true }, "go away"); n != 1 {
+			t.Fatalf("kicked %d sneks, want 1", n)
+		}
+
+		err := <-done
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("try %d: play returned %v, want a PermissionDenied kick", i, err)
+		}
+		metrics.mu.Lock()
+		got := metrics.outcomes[outcomeKicked]
+		metrics.mu.Unlock()
+		if got != kicked+1 {
+			t.Fatalf("try %d: %d kicks recorded, want %d", i, got, kicked+1)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/metadata"
+)
+
+// How far apart teammates start out
+const spawnGap = 6
+
+func (r *room) teamGame() bool {
+	return r.config.Mode == pb.RoomMode_TEAMS
+}
+
+func (r *room) teamCount() int {
+	if r.config.Teams > 0 {
+		return int(r.config.Teams)
+	}
+	return 2
+}
+
+// members returns the sneks on a team, in the order they joined. It must be
+// called with the room locked.
+func (r *room) members(team int) []*snek {
+	var sneks []*snek
+	for _, snek := range r.sneks {
+		if snek.team == team {
+			sneks = append(sneks, snek)
+		}
+	}
+	sort.Slice(sneks, func(i, j int) bool { return sneks[i].id < sneks[j].id })
+	return sneks
+}
+
+// pickTeam puts a snek on the team it asked for with a "team" in its request
+// metadata, or on whichever team is smallest. It must be called with the room
+// locked.
+func (r *room) pickTeam(snek *snek) {
+	md, _ := metadata.FromIncomingContext(snek.stream.Context())
+	if want, err := strconv.Atoi(first(md, "team")); err == nil && want > 0 && want <= r.teamCount() {
+		snek.team = want
+		return
+	}
+	// Take the snek off any team first, so it doesn't count itself
+	snek.team = 0
+	best := 1
+	for t := 2; t <= r.teamCount(); t++ {
+		if len(r.members(t)) < len(r.members(best)) {
+			best = t
+		}
+	}
+	snek.team = best
+}
+
+// spawn returns where a snek should start out in a team game. Each team gets
+// its own column of the board, with teammates spread out above and below each
+// other. It must be called with the room locked, after the snek has joined.
+func (r *room) spawn(snek *snek) rules.Loc {
+	members := r.members(snek.team)
+	i := 0
+	for i < len(members) && members[i] != snek {
+		i++
+	}
+	// Alternate between above and below the middle
+	dy := (i + 1) / 2 * spawnGap
+	if i%2 == 1 {
+		dy = -dy
+	}
+	l := rules.Loc{X: pb.BoardWidth * (2*snek.team - 1) / (2 * r.teamCount()), Y: pb.BoardHeight/2 + dy}
+	if !r.board().InBounds(l) {
+		l.Y = pb.BoardHeight / 2
+	}
+	if !r.board().InBounds(l) {
+		l.X = pb.BoardWidth / 2
+	}
+	return l
+}
+
+// balanceTeams puts everyone who isn't on a team yet on one, for when a room
+// switches to a team game. It must be called with the room locked.
+func (r *room) balanceTeams() {
+	var ids []ID
+	for id := range r.sneks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if snek := r.sneks[id]; snek.team == 0 || snek.team > r.teamCount() {
+			r.pickTeam(snek)
+		}
+	}
+}
+
+// collision returns the snek whose body the given snek's head ran into, if
+// that's against the rules of the team game. It must be called with the room
+// locked.
+func (r *room) collision(from *snek) *snek {
+	if len(from.body.Body) == 0 {
+		return nil
+	}
+	head := from.body.Head()
+	for _, snek := range r.sneks {
+		if snek == from || snek.moves.dead || !snek.body.Covers(head) {
+			continue
+		}
+		if snek.team == from.team && !r.config.FriendlyFire {
+			continue
+		}
+		return snek
+	}
+	return nil
+}
+
+// crash kills a snek that ran into another one, and gives the other one's team
+// the kill. It must be called with the room locked.
+func (r *room) crash(from, into *snek, tick int64) error {
+	if into.team != from.team {
+		into.kills++
+	}
+	from.send(&pb.UpdateResponse{Notice: fmt.Sprintf("You ran into %s", into.displayName())})
+	return r.kill(from, tick)
+}
+
+// teams returns every team, its members and its score. It must be called with
+// the room locked.
+func (r *room) teams() []*pb.Team {
+	var teams []*pb.Team
+	for t := 1; t <= r.teamCount(); t++ {
+		team := &pb.Team{Number: int32(t)}
+		for _, snek := range r.members(t) {
+			team.Members = append(team.Members, int32(snek.id))
+			switch r.config.Scoring {
+			case pb.TeamScoring_KILLS:
+				team.Score += int32(snek.kills)
+			case pb.TeamScoring_LENGTH:
+				if !snek.moves.dead {
+					team.Score += int32(len(snek.body.Body))
+				}
+			}
+		}
+		teams = append(teams, team)
+	}
+	return teams
+}
+
+// updateTeams tells everyone about the teams if they've changed since we last
+// did. It must be called with the room locked.
+func (r *room) updateTeams() error {
+	if !r.teamGame() {
+		return nil
+	}
+	teams := r.teams()
+	if r.lastTeams != nil && proto.Equal(&pb.UpdateResponse{Teams: teams}, &pb.UpdateResponse{Teams: r.lastTeams}) {
+		return nil
+	}
+	r.lastTeams = teams
+	return r.broadcast(&pb.UpdateResponse{Teams: teams})
+}
+
+// teammates returns whether two sneks are on the same team in a team game.
+func (r *room) teammates(a, b *snek) bool {
+	return r.teamGame() && a.team == b.team
+}
@@ -0,0 +1,537 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	resultsDir  = flag.String("results_dir", "", "a directory to write tournament standings to as <name>.json, empty to not write them")
+	joinTimeout = flag.Duration("match_join_timeout", 30*time.Second, "how long a tournament match waits for its players to join before they forfeit")
+)
+
+const (
+	defaultMatchTicks = 2000
+	// How often the referee checks on a match
+	refereeInterval = 100 * time.Millisecond
+)
+
+// Tournament names end up in room and file names, so we keep them simple.
+var validTournamentName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tournament is a competition between a fixed set of entrants, played out in
+// two player matches, each in its own room.
+type tournament struct {
+	sync.Mutex
+	config *pb.TournamentConfig
+	// The standings and matches so far, which is what we publish
+	info *pb.TournamentInfo
+}
+
+func newTournament(cfg *pb.TournamentConfig) *tournament {
+	if cfg.MatchTicks <= 0 {
+		cfg.MatchTicks = defaultMatchTicks
+	}
+	t := &tournament{
+		config: cfg,
+		info: &pb.TournamentInfo{
+			Name:      cfg.Name,
+			Format:    cfg.Format,
+			State:     "scheduled",
+			StartUnix: cfg.StartUnix,
+		},
+	}
+	for _, e := range cfg.Entrants {
+		t.info.Standings = append(t.info.Standings, &pb.Standing{Name: e.Name})
+	}
+	return t
+}
+
+// checkEntrant returns an error if the entrant can't join the tournament, and
+// must be called with the tournament locked.
+func (t *tournament) checkEntrant(e *pb.Entrant) error {
+	if e == nil || e.Name == "" {
+		return grpc.Errorf(codes.InvalidArgument, "entrants need a name")
+	}
+	for _, s := range t.info.Standings {
+		if s.Name == e.Name {
+			return grpc.Errorf(codes.AlreadyExists, "%s is already in tournament %q", e.Name, t.config.Name)
+		}
+	}
+	return nil
+}
+
+// standing must be called with the tournament locked.
+func (t *tournament) standing(name string) *pb.Standing {
+	for _, s := range t.info.Standings {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// record adds the result of a match to the standings, and must be called with
+// the tournament locked.
+func (t *tournament) record(a, b, winner string) {
+	sa, sb := t.standing(a), t.standing(b)
+	sa.Played++
+	sb.Played++
+	switch winner {
+	case "":
+		sa.Draws++
+		sb.Draws++
+		sa.Points += 0.5
+		sb.Points += 0.5
+	case a:
+		sa.Wins++
+		sb.Losses++
+		sa.Points++
+	case b:
+		sb.Wins++
+		sa.Losses++
+		sb.Points++
+	}
+}
+
+// rank sorts the standings best first, and must be called with the tournament
+// locked. Ties go to whoever was seeded higher.
+func (t *tournament) rank() {
+	seed := make(map[string]int)
+	for i, e := range t.config.Entrants {
+		seed[e.Name] = i
+	}
+	sort.SliceStable(t.info.Standings, func(i, j int) bool {
+		a, b := t.info.Standings[i], t.info.Standings[j]
+		if a.Eliminated != b.Eliminated {
+			return b.Eliminated
+		}
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.Wins != b.Wins {
+			return a.Wins > b.Wins
+		}
+		return seed[a.Name] < seed[b.Name]
+	})
+}
+
+func (t *tournament) snapshot() *pb.TournamentInfo {
+	t.Lock()
+	defer t.Unlock()
+	return proto.Clone(t.info).(*pb.TournamentInfo)
+}
+
+// save writes the tournament's standings out to the results directory, if we
+// have one.
+func (t *tournament) save() {
+	if *resultsDir == "" {
+		return
+	}
+	buf, err := json.MarshalIndent(t.snapshot(), "", "  ")
+	if err != nil {
+		log.Printf("[%s] save(): %v", t.config.Name, err)
+		return
+	}
+	// Write to a temporary file first, so nobody reading the results sees half
+	// of them
+	fn := filepath.Join(*resultsDir, t.config.Name+".json")
+	if err := ioutil.WriteFile(fn+".tmp", buf, 0644); err != nil {
+		log.Printf("[%s] save(): %v", t.config.Name, err)
+		return
+	}
+	if err := os.Rename(fn+".tmp", fn); err != nil {
+		log.Printf("[%s] save(): %v", t.config.Name, err)
+	}
+}
+
+func (s *server) tournament(name string) (*tournament, error) {
+	s.Lock()
+	defer s.Unlock()
+	t, ok := s.tournaments[name]
+	if !ok {
+		return nil, grpc.Errorf(codes.NotFound, "no tournament named %q", name)
+	}
+	return t, nil
+}
+
+func (s *server) Tournament(ctx context.Context, req *pb.TournamentRequest) (*pb.TournamentInfo, error) {
+	t, err := s.tournament(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	info := t.snapshot()
+	info.You = s.auth.name(ctx)
+	return info, nil
+}
+
+func (a *adminServer) CreateTournament(ctx context.Context, cfg *pb.TournamentConfig) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if !validTournamentName.MatchString(cfg.Name) {
+		return nil, grpc.Errorf(codes.InvalidArgument, "tournament names can only have letters, numbers, dashes and underscores")
+	}
+
+	t := newTournament(&pb.TournamentConfig{
+		Name:       cfg.Name,
+		Format:     cfg.Format,
+		StartUnix:  cfg.StartUnix,
+		MatchTicks: cfg.MatchTicks,
+	})
+	for _, e := range cfg.Entrants {
+		if err := t.checkEntrant(e); err != nil {
+			return nil, err
+		}
+		t.config.Entrants = append(t.config.Entrants, e)
+		t.info.Standings = append(t.info.Standings, &pb.Standing{Name: e.Name})
+	}
+
+	s := a.srv
+	s.Lock()
+	if _, ok := s.tournaments[cfg.Name]; ok {
+		s.Unlock()
+		return nil, grpc.Errorf(codes.AlreadyExists, "there's already a tournament named %q", cfg.Name)
+	}
+	s.tournaments[cfg.Name] = t
+	s.Unlock()
+
+	log.Printf("Admin created tournament %q with %d entrants", cfg.Name, len(cfg.Entrants))
+	t.save()
+	go s.runTournament(t)
+	return &pb.AdminResult{Affected: int32(len(cfg.Entrants))}, nil
+}
+
+func (a *adminServer) AddEntrant(ctx context.Context, req *pb.AddEntrantRequest) (*pb.AdminResult, error) {
+	if err := a.authorize(ctx); err != nil {
+		return nil, err
+	}
+	t, err := a.srv.tournament(req.Tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Lock()
+	if t.info.State != "scheduled" {
+		t.Unlock()
+		return nil, grpc.Errorf(codes.FailedPrecondition, "tournament %q has already started", req.Tournament)
+	}
+	if err := t.checkEntrant(req.Entrant); err != nil {
+		t.Unlock()
+		return nil, err
+	}
+	t.config.Entrants = append(t.config.Entrants, req.Entrant)
+	t.info.Standings = append(t.info.Standings, &pb.Standing{Name: req.Entrant.Name})
+	t.Unlock()
+
+	log.Printf("Admin added %s to tournament %q", req.Entrant.Name, req.Tournament)
+	t.save()
+	return &pb.AdminResult{Affected: 1}, nil
+}
+
+// runTournament waits for the tournament to start, then plays it out one round
+// at a time. The matches in each round are played at the same time.
+func (s *server) runTournament(t *tournament) {
+	if d := time.Unix(t.config.StartUnix, 0).Sub(time.Now()); d > 0 {
+		time.Sleep(d)
+	}
+
+	t.Lock()
+	t.info.State = "running"
+	entrants := append([]*pb.Entrant(nil), t.config.Entrants...)
+	t.Unlock()
+	t.save()
+	log.Printf("[%s] Starting %s tournament with %d entrants", t.config.Name, t.config.Format, len(entrants))
+
+	var champion string
+	if t.config.Format == pb.TournamentFormat_SINGLE_ELIMINATION {
+		champion = s.runElimination(t, entrants)
+	} else {
+		champion = s.runRoundRobin(t, entrants)
+	}
+
+	t.Lock()
+	t.info.State = "finished"
+	t.info.Champion = champion
+	t.rank()
+	t.Unlock()
+	t.save()
+	log.Printf("[%s] Tournament over, %q is the champion", t.config.Name, champion)
+}
+
+func (s *server) runRoundRobin(t *tournament, entrants []*pb.Entrant) string {
+	for i, pairs := range roundRobin(entrants) {
+		if s.isDraining() {
+			return ""
+		}
+		s.playRound(t, i+1, pairs)
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	t.rank()
+	if len(t.info.Standings) == 0 {
+		return ""
+	}
+	return t.info.Standings[0].Name
+}
+
+// roundRobin pairs everyone up with everyone else, using the circle method to
+// split the pairings into rounds where everybody plays at most once.
+func roundRobin(entrants []*pb.Entrant) [][][2]*pb.Entrant {
+	players := append([]*pb.Entrant(nil), entrants...)
+	if len(players)%2 == 1 {
+		// Whoever gets paired with nil sits the round out
+		players = append(players, nil)
+	}
+	n := len(players)
+
+	var rounds [][][2]*pb.Entrant
+	for r := 0; r < n-1; r++ {
+		var pairs [][2]*pb.Entrant
+		for i := 0; i < n/2; i++ {
+			a, b := players[i], players[n-1-i]
+			if a != nil && b != nil {
+				pairs = append(pairs, [2]*pb.Entrant{a, b})
+			}
+		}
+		rounds = append(rounds, pairs)
+		// Keep the first player where they are and rotate everyone else
+		players = append([]*pb.Entrant{players[0], players[n-1]}, players[1:n-1]...)
+	}
+	return rounds
+}
+
+// runElimination plays rounds until there's only one entrant left. Each round
+// the best seeds left play the worst, with the best seed getting a bye if
+// there's an odd number of players.
+func (s *server) runElimination(t *tournament, entrants []*pb.Entrant) string {
+	left := entrants
+	for round := 1; len(left) > 1; round++ {
+		if s.isDraining() {
+			return ""
+		}
+		var next []*pb.Entrant
+		if len(left)%2 == 1 {
+			next, left = append(next, left[0]), left[1:]
+		}
+		var pairs [][2]*pb.Entrant
+		for i := 0; i < len(left)/2; i++ {
+			pairs = append(pairs, [2]*pb.Entrant{left[i], left[len(left)-1-i]})
+		}
+
+		winners := s.playRound(t, round, pairs)
+		won := make(map[string]bool)
+		t.Lock()
+		for i, p := range pairs {
+			// Draws go to the better seed, which is always first
+			w := winners[i]
+			if w == "" {
+				w = p[0].Name
+			}
+			won[w] = true
+			for _, e := range p {
+				if e.Name != w {
+					t.standing(e.Name).Eliminated = true
+				}
+			}
+		}
+		t.rank()
+		t.Unlock()
+		t.save()
+
+		// Keep everyone who's left in seed order
+		for _, e := range left {
+			if won[e.Name] {
+				next = append(next, e)
+			}
+		}
+		sort.SliceStable(next, func(i, j int) bool { return seedOf(entrants, next[i]) < seedOf(entrants, next[j]) })
+		left = next
+	}
+	if len(left) == 0 {
+		return ""
+	}
+	return left[0].Name
+}
+
+func seedOf(entrants []*pb.Entrant, e *pb.Entrant) int {
+	for i, o := range entrants {
+		if o == e {
+			return i
+		}
+	}
+	return len(entrants)
+}
+
+// playRound plays every match in a round at once, and returns who won each of
+// them, with an empty name for a draw.
+func (s *server) playRound(t *tournament, round int, pairs [][2]*pb.Entrant) []string {
+	winners := make([]string, len(pairs))
+	var wg sync.WaitGroup
+	for i, p := range pairs {
+		wg.Add(1)
+		go func(i int, a, b *pb.Entrant) {
+			defer wg.Done()
+			winners[i] = s.playMatch(t, round, i+1, a, b)
+		}(i, p[0], p[1])
+	}
+	wg.Wait()
+	return winners
+}
+
+func (s *server) playMatch(t *tournament, round, num int, a, b *pb.Entrant) string {
+	room := fmt.Sprintf("%s-r%d-m%d", t.config.Name, round, num)
+	match := &pb.MatchInfo{Room: room, Round: int32(round), Entrants: []string{a.Name, b.Name}}
+	t.Lock()
+	t.info.Matches = append(t.info.Matches, match)
+	t.Unlock()
+	t.save()
+
+	// The referee goes by name, so only the two of them can be in the room
+	s.restrictRoom(room, map[string]bool{a.Name: true, b.Name: true})
+	for _, e := range []*pb.Entrant{a, b} {
+		if e.BotUrl != "" {
+			go s.addBot(room, e.Name, e.BotUrl, false)
+		}
+	}
+	winner := s.referee(room, a.Name, b.Name, t.config.MatchTicks)
+	// If nobody showed up, the room never opened to take the roster
+	s.Lock()
+	delete(s.roomRosters, room)
+	s.Unlock()
+	log.Printf("[%s] %s vs %s: winner %q", room, a.Name, b.Name, winner)
+
+	t.Lock()
+	match.Winner, match.Done = winner, true
+	t.record(a.Name, b.Name, winner)
+	t.rank()
+	t.Unlock()
+	t.save()
+	return winner
+}
+
+// matchStatus is how a player in a match is doing.
+type matchStatus struct {
+	present bool
+	alive   bool
+	length  int
+}
+
+// statuses returns how the named players in the room are doing, and the room's
+// current tick.
+func (r *room) statuses(names ...string) (int64, map[string]matchStatus) {
+	r.Lock()
+	defer r.Unlock()
+	sts := make(map[string]matchStatus)
+	for _, snek := range r.sneks {
+		for _, name := range names {
+			if snek.name != name {
+				continue
+			}
+			st := sts[name]
+			st.present = true
+			if !snek.moves.dead {
+				st.alive = true
+				if len(snek.body.Body) > st.length {
+					st.length = len(snek.body.Body)
+				}
+			}
+			sts[name] = st
+		}
+	}
+	return r.tick, sts
+}
+
+// referee waits for both players to join the room, then watches the match
+// until one of them is out or time's up, and returns who won. Players who don't
+// show up forfeit, and when both sneks die at once or time runs out, the
+// longer snek wins.
+func (s *server) referee(roomName, a, b string, ticks int64) string {
+	var r *room
+	deadline := time.Now().Add(*joinTimeout)
+	for {
+		if r, _ = s.room(roomName); r != nil {
+			_, sts := r.statuses(a, b)
+			if sts[a].present && sts[b].present {
+				break
+			}
+			if time.Now().After(deadline) {
+				r.end("Your opponent didn't show up")
+				switch {
+				case sts[a].present:
+					return a
+				case sts[b].present:
+					return b
+				}
+				return ""
+			}
+		} else if time.Now().After(deadline) {
+			return ""
+		}
+		time.Sleep(refereeInterval)
+	}
+
+	start, _ := r.statuses()
+	r.announce(fmt.Sprintf("Match on: %s vs %s", a, b))
+
+	// The last we saw of each of them
+	last := map[string]matchStatus{a: {alive: true}, b: {alive: true}}
+	var winner string
+	for {
+		time.Sleep(refereeInterval)
+		tick, sts := r.statuses(a, b)
+		for _, name := range []string{a, b} {
+			st := sts[name]
+			if st.alive {
+				last[name] = st
+			} else {
+				last[name] = matchStatus{length: last[name].length}
+			}
+		}
+
+		aliveA, aliveB := last[a].alive, last[b].alive
+		if aliveA && aliveB && tick-start < ticks {
+			continue
+		}
+		switch {
+		case aliveA && !aliveB:
+			winner = a
+		case aliveB && !aliveA:
+			winner = b
+		case last[a].length > last[b].length:
+			winner = a
+		case last[b].length > last[a].length:
+			winner = b
+		}
+		break
+	}
+
+	if winner == "" {
+		r.end("Match over, it's a draw")
+	} else {
+		r.end(fmt.Sprintf("Match over, %s wins", winner))
+	}
+	return winner
+}
+
+func (s *server) isDraining() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.draining
+}
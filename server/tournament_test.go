@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMatchRoomTurnsAwayStrangers(t *testing.T) {
+	defer func(d time.Duration) { *joinTimeout = d }(*joinTimeout)
+	*joinTimeout = 500 * time.Millisecond
+
+	s := newTestServer(t)
+	alice, bob := &pb.Entrant{Name: "alice"}, &pb.Entrant{Name: "bob"}
+	tm := newTournament(&pb.TournamentConfig{Name: "cup", Entrants: []*pb.Entrant{alice, bob}})
+	winner := make(chan string, 1)
+	go func() { winner <- s.playMatch(tm, 1, 1, alice, bob) }()
+	for start := time.Now(); ; time.Sleep(time.Millisecond) {
+		s.Lock()
+		_, ok := s.roomRosters["cup-r1-m1"]
+		s.Unlock()
+		if ok {
+			break
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("the match room never got a roster")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := s.play(newFakeStream(ctx), "cup-r1-m1", &identity{name: "mallory"}, "")
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("a stranger joining the match got %v, want PermissionDenied", err)
+	}
+
+	// Bob doesn't show, so alice wins by forfeit
+	done := make(chan error, 1)
+	go func() { done <- s.play(newFakeStream(ctx), "cup-r1-m1", &identity{name: "alice"}, "") }()
+	if got := <-winner; got != "alice" {
+		t.Errorf("playMatch() = %q, want alice", got)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("alice's stream ended with %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+var maxViolations = flag.Int("max_violations", 10, "how many illegal moves a snek can make before it gets kicked, 0 to never kick")
+
+// moveLog is what we know about the moves a snek has made, so we can check
+// that each new one is legal.
+type moveLog struct {
+	// The tick of the last move we accepted, sneks only get one move a tick
+	tick int64
+	// Where the snek's head was after its last move
+	head Loc
+	// Which way it was going
+	dir Loc
+	// Where the snek said its tail was before its last move. Sneks start out
+	// coiled up, so until the tail comes off the spawn cell, that's where it is
+	tail Loc
+	// The cell the snek was told to start on, in games that pick one
+	spawn    Loc
+	assigned bool
+	// Whether it has moved at all yet
+	moved bool
+	// Whether it has died, after which it can't move at all
+	dead bool
+
+	violations int
+}
+
+type Loc struct {
+	X, Y int
+}
+
+func locFromPB(l *pb.Loc) Loc {
+	return Loc{X: int(l.GetX()), Y: int(l.GetY())}
+}
+
+func (l Loc) inBounds() bool {
+	return l.X >= 0 && l.Y >= 0 && l.X < pb.BoardWidth && l.Y < pb.BoardHeight
+}
+
+// step returns the direction it takes to get from one cell to a neighboring
+// one, optionally wrapping around the edges of the board.
+func step(from, to Loc, wrap bool) (Loc, bool) {
+	d := Loc{to.X - from.X, to.Y - from.Y}
+	if wrap {
+		d = Loc{wrapDelta(d.X, pb.BoardWidth), wrapDelta(d.Y, pb.BoardHeight)}
+	}
+	switch d {
+	case Loc{0, -1}, Loc{0, 1}, Loc{-1, 0}, Loc{1, 0}:
+		return d, true
+	}
+	return d, false
+}
+
+// wrapDelta turns a jump from one edge of the board to the other into a single
+// step.
+func wrapDelta(d, size int) int {
+	switch d {
+	case size - 1:
+		return -1
+	case -(size - 1):
+		return 1
+	}
+	return d
+}
+
+// check returns an error if the move isn't legal, otherwise it records it as
+// the snek's latest move. tick is the tick the server stamped the move with,
+// wrap is whether the room lets sneks wrap around the edges, and body is the
+// snek's body as the server has followed it so far.
+func (m *moveLog) check(req *pb.UpdateRequest, tick int64, wrap bool, body []rules.Loc) error {
+	if m.dead {
+		return fmt.Errorf("snek is dead")
+	}
+	if req.NewHead == nil || req.OldTail == nil {
+		return fmt.Errorf("move is missing a head or tail")
+	}
+	if m.moved && tick <= m.tick {
+		return fmt.Errorf("already moved on tick %d", m.tick)
+	}
+
+	head := locFromPB(req.NewHead)
+	if !head.inBounds() {
+		return fmt.Errorf("head %v is off the board", head)
+	}
+
+	if m.moved {
+		dir, ok := step(m.head, head, wrap)
+		if !ok {
+			return fmt.Errorf("head jumped from %v to %v", m.head, head)
+		}
+		if dir.X == -m.dir.X && dir.Y == -m.dir.Y {
+			return fmt.Errorf("reversed direction at %v", m.head)
+		}
+		m.dir = dir
+	}
+
+	tail := locFromPB(req.OldTail)
+	if !tail.inBounds() {
+		return fmt.Errorf("tail %v is off the board", tail)
+	}
+	if m.moved {
+		if tail != m.tail && tail != nextTail(m.tail, body) {
+			return fmt.Errorf("tail jumped from %v to %v", m.tail, tail)
+		}
+	} else {
+		// The first move is off the cell the snek is coiled up on
+		if m.assigned && tail != m.spawn {
+			return fmt.Errorf("started at %v, not the spawn at %v", tail, m.spawn)
+		}
+		if _, ok := step(tail, head, wrap); !ok {
+			return fmt.Errorf("head jumped from %v to %v", tail, head)
+		}
+	}
+
+	m.tick, m.head, m.tail, m.moved = tick, head, tail, true
+	return nil
+}
+
+// nextTail returns where a tail goes when it moves on from the given cell,
+// which is the next cell along the body, or the first one if the tail has
+// already left it behind.
+func nextTail(tail Loc, body []rules.Loc) Loc {
+	for i, c := range body {
+		if (Loc{c.X, c.Y}) != tail {
+			continue
+		}
+		if i+1 < len(body) {
+			return Loc{body[i+1].X, body[i+1].Y}
+		}
+		return tail
+	}
+	if len(body) == 0 {
+		return tail
+	}
+	return Loc{body[0].X, body[0].Y}
+}
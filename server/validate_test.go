@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+)
+
+func TestCheckTail(t *testing.T) {
+	type move struct{ head, tail Loc }
+	tests := []struct {
+		desc  string
+		spawn *Loc
+		moves []move
+		// Whether the last move should be turned down
+		wantErr bool
+	}{
+		{
+			desc:  "uncoiling",
+			moves: []move{{Loc{6, 5}, Loc{5, 5}}, {Loc{7, 5}, Loc{5, 5}}, {Loc{8, 5}, Loc{5, 5}}},
+		},
+		{
+			desc:  "tail comes off the spawn",
+			moves: []move{{Loc{6, 5}, Loc{5, 5}}, {Loc{7, 5}, Loc{5, 5}}, {Loc{8, 5}, Loc{6, 5}}, {Loc{9, 5}, Loc{7, 5}}},
+		},
+		{
+			desc:  "tail stays put after eating",
+			moves: []move{{Loc{6, 5}, Loc{5, 5}}, {Loc{7, 5}, Loc{6, 5}}, {Loc{8, 5}, Loc{6, 5}}, {Loc{9, 5}, Loc{7, 5}}},
+		},
+		{
+			desc:    "first tail isn't next to the head",
+			moves:   []move{{Loc{6, 5}, Loc{20, 20}}},
+			wantErr: true,
+		},
+		{
+			desc:    "tail jumps",
+			moves:   []move{{Loc{6, 5}, Loc{5, 5}}, {Loc{7, 5}, Loc{30, 30}}},
+			wantErr: true,
+		},
+		{
+			desc:    "tail skips a cell",
+			moves:   []move{{Loc{6, 5}, Loc{5, 5}}, {Loc{7, 5}, Loc{5, 5}}, {Loc{8, 5}, Loc{7, 5}}},
+			wantErr: true,
+		},
+		{
+			desc:    "tail off the board",
+			moves:   []move{{Loc{0, 5}, Loc{-1, 5}}},
+			wantErr: true,
+		},
+		{
+			desc:  "spawn",
+			spawn: &Loc{5, 5},
+			moves: []move{{Loc{5, 4}, Loc{5, 5}}},
+		},
+		{
+			desc:    "away from the spawn",
+			spawn:   &Loc{5, 5},
+			moves:   []move{{Loc{11, 10}, Loc{10, 10}}},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		var m moveLog
+		if test.spawn != nil {
+			m.spawn, m.assigned = *test.spawn, true
+		}
+		var body rules.Snek
+		for i, mv := range test.moves {
+			req := &pb.UpdateRequest{
+				NewHead: &pb.Loc{X: int32(mv.head.X), Y: int32(mv.head.Y)},
+				OldTail: &pb.Loc{X: int32(mv.tail.X), Y: int32(mv.tail.Y)},
+			}
+			err := m.check(req, int64(i+1), false, body.Body)
+			if last := i == len(test.moves)-1; !last || !test.wantErr {
+				if err != nil {
+					t.Errorf("%s: move %d: check() = %v", test.desc, i+1, err)
+				}
+			} else if err == nil {
+				t.Errorf("%s: move %d: check() = nil, want an error", test.desc, i+1)
+			}
+			body.Follow(ruleLoc(req.NewHead), ruleLoc(req.OldTail))
+		}
+	}
+}
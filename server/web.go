@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var webAddr = flag.String("web_addr", "", "an address to serve the browser client and its WebSocket gateway on, e.g. ':8080', empty to disable")
+
+// webPage is the browser client, sized to our board.
+var webPage = strings.NewReplacer(
+	"BOARD_WIDTH", strconv.Itoa(pb.BoardWidth),
+	"BOARD_HEIGHT", strconv.Itoa(pb.BoardHeight),
+).Replace(webClient)
+
+// The browser client talks to the server over a WebSocket at /ws, sending
+// UpdateRequests and receiving UpdateResponses as JSON text messages, with the
+// same field names as the proto. It picks its room and passes its credentials
+// in the query string, e.g. /ws?room=arena&token=abc, since browsers can't set
+// headers on WebSocket requests. Passwords in the query string are only as
+// private as the connection, so use -tls_cert if they're going over the
+// internet.
+
+// wsStream adapts a WebSocket connection to an updateStream.
+type wsStream struct {
+	ws  *websocket.Conn
+	ctx context.Context
+}
+
+func (w *wsStream) Send(resp *pb.UpdateResponse) error {
+	return websocket.JSON.Send(w.ws, resp)
+}
+
+func (w *wsStream) Recv() (*pb.UpdateRequest, error) {
+	var req pb.UpdateRequest
+	if err := websocket.JSON.Receive(w.ws, &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (w *wsStream) Context() context.Context {
+	return w.ctx
+}
+
+// serveWebSocket puts a browser snek in a room, the same way Update does for
+// gRPC ones.
+func (s *server) serveWebSocket(ws *websocket.Conn) {
+	req := ws.Request()
+	q := req.URL.Query()
+	roomName := q.Get("room")
+	if roomName == "" {
+		roomName = defaultRoom
+	}
+
+	// Pass the credentials along like a gRPC client would, so the authenticator
+	// doesn't need to know the difference
+	var kv []string
+	if tok := q.Get("token"); tok != "" {
+		kv = append(kv, "authorization", "Bearer "+tok)
+	}
+	if user := q.Get("user"); user != "" {
+		kv = append(kv, "username", user, "password", q.Get("password"))
+	}
+	if team := q.Get("team"); team != "" {
+		kv = append(kv, "team", team)
+	}
+	ctx := metadata.NewIncomingContext(req.Context(), metadata.Pairs(kv...))
+	stream := &wsStream{ws: ws, ctx: ctx}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	id, err := s.auth.authenticate(ctx, roomName)
+	if err == nil {
+		err = s.play(stream, roomName, id, host)
+	}
+	if err != nil && err != io.EOF {
+		log.Printf("WebSocket snek from %s in room %q: %v", host, roomName, err)
+		// There's no status on a WebSocket, so tell them why we're hanging up
+		stream.Send(&pb.UpdateResponse{Notice: status.Convert(err).Message()})
+	}
+}
+
+// serveWeb serves the browser client at / and its WebSocket gateway at /ws, over
+// TLS if we have a certificate.
+func (s *server) serveWeb(addr, certFile, keyFile string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, webPage)
+	})
+	mux.Handle("/ws", websocket.Handler(s.serveWebSocket))
+
+	var err error
+	if certFile != "" {
+		log.Printf("Serving the browser client on https://%s", addr)
+		err = http.ListenAndServeTLS(addr, certFile, keyFile, mux)
+	} else {
+		log.Printf("Serving the browser client on http://%s", addr)
+		err = http.ListenAndServe(addr, mux)
+	}
+	if err != nil {
+		log.Printf("failed to serve the browser client: %v", err)
+	}
+}
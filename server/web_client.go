@@ -0,0 +1,224 @@
+package main
+
+// webClient is the browser client served by serveWeb. It plays the same game
+// as the terminal client: we move on the server's ticks, eat our own food, and
+// send each move to the room, drawing everyone else's as they come in.
+const webClient = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Snek</title>
+<style>
+  body { background: #111; color: #eee; font-family: monospace; text-align: center; }
+  canvas { background: #000; border: 1px solid #eee; margin-top: 1em; }
+  #status { display: flex; justify-content: space-between; width: 576px; margin: 0.5em auto; }
+</style>
+</head>
+<body>
+<canvas id="board"></canvas>
+<div id="status"><span id="notice">Connecting...</span><span id="scores"></span><span id="ping"></span></div>
+<p>Arrow keys or WASD to steer. Add ?room=name&amp;token=... to the URL to pick a room or sign in, and &amp;team=n to pick a team.</p>
+<script>
+"use strict";
+const W = BOARD_WIDTH, H = BOARD_HEIGHT, CELL = 12;
+const COLORS = ["#e33", "#3c3", "#ee3", "#36f", "#e3e", "#3ee", "#fff"];
+// Everyone on a team shares its colour
+const TEAM_COLORS = ["#e33", "#36f", "#3c3", "#ee3", "#e3e", "#3ee"];
+const DIRS = {
+  ArrowUp: [0, -1], ArrowDown: [0, 1], ArrowLeft: [-1, 0], ArrowRight: [1, 0],
+  w: [0, -1], s: [0, 1], a: [-1, 0], d: [1, 0],
+};
+
+const canvas = document.getElementById("board");
+canvas.width = W * CELL;
+canvas.height = H * CELL;
+const ctx = canvas.getContext("2d");
+
+// Our snek, with its head at the end, starting out coiled up in the middle
+let body = [];
+for (let i = 0; i < 10; i++) body.push({x: W / 2, y: H / 2});
+let dir = [1, 0], nextDirs = [];
+let wrap = false, tick = 0, alive = true;
+// Our ID, and how many cells in from each edge are out of play
+let you = 0, margin = 0;
+let food = null;
+// Other sneks, by ID, and the teams they're on in team games
+const opponents = {};
+let teamOf = {};
+let colorCount = 0;
+// When we sent each of our moves, by tick, to measure our ping
+const sent = {};
+
+const proto = location.protocol === "https:" ? "wss:" : "ws:";
+const ws = new WebSocket(proto + "//" + location.host + "/ws" + location.search);
+
+function same(a, b) { return a.x === b.x && a.y === b.y; }
+function loc(l) { return {x: (l && l.x) || 0, y: (l && l.y) || 0}; }
+
+function fill(l, color) {
+  ctx.fillStyle = color;
+  ctx.fillRect(l.x * CELL, l.y * CELL, CELL, CELL);
+}
+
+function inBounds(l) {
+  return l.x >= margin && l.y >= margin && l.x < W - margin && l.y < H - margin;
+}
+
+function newFood() {
+  do {
+    food = {x: Math.floor(Math.random() * W), y: Math.floor(Math.random() * H)};
+  } while (!inBounds(food));
+  ctx.fillStyle = "#fff";
+  ctx.beginPath();
+  ctx.arc((food.x + 0.5) * CELL, (food.y + 0.5) * CELL, CELL / 3, 0, 2 * Math.PI);
+  ctx.fill();
+}
+
+function notice(msg) {
+  document.getElementById("notice").textContent = msg;
+}
+
+function die(msg) {
+  alive = false;
+  notice(msg + " Reload to play again");
+}
+
+function colorOf(id) {
+  const t = teamOf[id];
+  return t ? TEAM_COLORS[(t - 1) % TEAM_COLORS.length] : COLORS[colorCount++ % COLORS.length];
+}
+
+// closeIn shades in the parts of the board that are now out of play, and
+// kills us if we were caught out there
+function closeIn(m) {
+  margin = m;
+  for (let x = 0; x < W; x++) {
+    for (let y = 0; y < H; y++) {
+      if (!inBounds({x: x, y: y})) fill({x: x, y: y}, "#400");
+    }
+  }
+  if (body.some(l => !inBounds(l))) {
+    ws.send(JSON.stringify({tick: tick, dead: true}));
+    die("The arena closed in on you!");
+    return;
+  }
+  if (!inBounds(food)) newFood();
+}
+
+function setTeams(teams) {
+  teamOf = {};
+  const scores = [];
+  for (const t of teams) {
+    for (const id of t.members || []) teamOf[id] = t.number;
+    scores.push("Team " + t.number + ": " + (t.score || 0));
+  }
+  document.getElementById("scores").textContent = scores.join("  ");
+  for (const id in opponents) {
+    const o = opponents[id];
+    o.color = colorOf(id);
+    for (const l of o.cells) fill(l, o.color);
+  }
+}
+
+// step moves our snek one cell, the same way the terminal client does
+function step() {
+  if (!alive) return;
+  if (nextDirs.length > 0) dir = nextDirs.shift();
+  tick++;
+
+  const h = body[body.length - 1];
+  const nh = {x: h.x + dir[0], y: h.y + dir[1]};
+  if (wrap) {
+    nh.x = (nh.x + W) % W;
+    nh.y = (nh.y + H) % H;
+  }
+  if (!inBounds(nh) || body.some(l => same(l, nh))) {
+    ws.send(JSON.stringify({tick: tick, dead: true}));
+    die("You died!");
+    return;
+  }
+  body.push(nh);
+  fill(nh, "#fff");
+  if (same(nh, food)) {
+    // Grow by leaving an extra copy of our tail behind
+    body.unshift(body[0]);
+    newFood();
+  }
+  const t = body.shift();
+  if (!body.some(l => same(l, t))) fill(t, "#000");
+
+  sent[tick] = performance.now();
+  ws.send(JSON.stringify({new_head: nh, old_tail: t, tick: tick}));
+}
+
+function receive(resp) {
+  const id = resp.id || 0;
+  if (id === 0) {
+    if (resp.config) wrap = !!resp.config.wrap;
+    if (resp.you) you = resp.you;
+    if (resp.spawn) {
+      // Start over where the server wants us
+      for (const l of body) fill(l, "#000");
+      body = [];
+      for (let i = 0; i < 10; i++) body.push(loc(resp.spawn));
+      fill(body[0], "#fff");
+    }
+    if (resp.teams) setTeams(resp.teams);
+    if (resp.notice) notice(resp.notice);
+    // Line ourselves up with the server's clock
+    if (resp.tick && resp.tick > tick) {
+      tick = resp.tick - 1;
+      if ((resp.margin || 0) !== margin) closeIn(resp.margin || 0);
+      step();
+    }
+    return;
+  }
+  if (resp.dead && id === you) {
+    if (alive) die("You ran into someone!");
+    return;
+  }
+  if (resp.ack) {
+    const start = sent[resp.tick];
+    if (start !== undefined) {
+      document.getElementById("ping").textContent = "ping: " + Math.round(performance.now() - start) + "ms";
+      delete sent[resp.tick];
+    }
+    return;
+  }
+
+  let o = opponents[id];
+  if (!o) {
+    o = opponents[id] = {color: colorOf(id), cells: []};
+  }
+  if (resp.dead) {
+    for (const l of o.cells) fill(l, "#000");
+    delete opponents[id];
+    return;
+  }
+  const head = loc(resp.new_head), tail = loc(resp.old_tail);
+  o.cells.push(head);
+  fill(head, o.color);
+  const i = o.cells.findIndex(l => same(l, tail));
+  if (i >= 0) o.cells.splice(i, 1);
+  if (!o.cells.some(l => same(l, tail))) fill(tail, "#000");
+}
+
+document.addEventListener("keydown", e => {
+  const d = DIRS[e.key];
+  if (!d) return;
+  e.preventDefault();
+  // Don't let the player turn back on themselves
+  const last = nextDirs.length > 0 ? nextDirs[nextDirs.length - 1] : dir;
+  if (d[0] !== -last[0] || d[1] !== -last[1]) nextDirs.push(d);
+});
+
+ws.onopen = () => notice("Connected");
+ws.onmessage = e => receive(JSON.parse(e.data));
+ws.onclose = () => { if (alive) notice(document.getElementById("notice").textContent + " (disconnected)"); };
+
+fill(body[0], "#fff");
+newFood();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,238 @@
+// Command snek_sim plays bots against each other without a server or a
+// terminal, and reports how each of them did. Every game is seeded, so a run
+// with the same flags always has the same results, however many games are
+// played at once.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/bcspragu/Snek/bot"
+	"github.com/bcspragu/Snek/rules"
+)
+
+// players are the bots we know how to play, each one is given its own seeded
+// source of randomness for every game.
+var players = map[string]func(rng *rand.Rand) bot.Player{
+	"greedy":   func(*rand.Rand) bot.Player { return bot.Greedy },
+	"wanderer": func(rng *rand.Rand) bot.Player { return &bot.Wanderer{Rand: rng, Turn: 0.1} },
+}
+
+var (
+	botList  = flag.String("bots", "greedy,wanderer", "a comma separated list of the bots in every game, from: "+strings.Join(playerNames(), ", "))
+	games    = flag.Int("games", 100, "how many games to play")
+	parallel = flag.Int("parallel", runtime.NumCPU(), "how many games to play at once")
+	seed     = flag.Int64("seed", 1, "the seed for the first game, each game after it uses the next one")
+	maxTicks = flag.Int64("max_ticks", 2000, "how long a game can go before it's called")
+	wrap     = flag.Bool("wrap", false, "whether sneks wrap around the edges of the board")
+	format   = flag.String("format", "table", "how to print the results, either 'table' or 'json'")
+)
+
+func playerNames() []string {
+	var names []string
+	for name := range players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// outcome is how one bot did in one game.
+type outcome struct {
+	// How many ticks it survived
+	ticks  int64
+	length int
+	won    bool
+}
+
+// stats are how one bot did across every game.
+type stats struct {
+	Bot       string  `json:"bot"`
+	Games     int     `json:"games"`
+	Wins      int     `json:"wins"`
+	WinRate   float64 `json:"win_rate"`
+	AvgLength float64 `json:"avg_length"`
+	AvgTicks  float64 `json:"avg_ticks"`
+	// How many games it was still alive at the end of
+	Survived int `json:"survived"`
+}
+
+func main() {
+	flag.Parse()
+
+	var names []string
+	for _, name := range strings.Split(*botList, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := players[name]; !ok {
+			log.Fatalf("unknown bot %q, must be one of: %s", name, strings.Join(playerNames(), ", "))
+		}
+		names = append(names, name)
+	}
+	if *format != "table" && *format != "json" {
+		log.Fatalf("unknown -format %q, must be 'table' or 'json'", *format)
+	}
+	if *parallel < 1 {
+		log.Fatalf("-parallel has to be at least 1, got %d", *parallel)
+	}
+
+	results := make([][]outcome, *games)
+	idx := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < *parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idx {
+				results[i] = play(names, *seed+int64(i))
+			}
+		}()
+	}
+	for i := 0; i < *games; i++ {
+		idx <- i
+	}
+	close(idx)
+	wg.Wait()
+
+	all := summarize(labels(names), results)
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BOT\tGAMES\tWINS\tWIN RATE\tAVG LENGTH\tAVG TICKS\tSURVIVED")
+	for _, s := range all {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\t%.1f\t%.1f\t%d\n", s.Bot, s.Games, s.Wins, s.WinRate*100, s.AvgLength, s.AvgTicks, s.Survived)
+	}
+	w.Flush()
+}
+
+// labels tells apart bots that are playing against themselves.
+func labels(names []string) []string {
+	count := make(map[string]int)
+	for _, name := range names {
+		count[name]++
+	}
+	seen := make(map[string]int)
+	var out []string
+	for _, name := range names {
+		seen[name]++
+		if count[name] > 1 {
+			name = fmt.Sprintf("%s#%d", name, seen[name])
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+func summarize(labels []string, results [][]outcome) []stats {
+	all := make([]stats, len(labels))
+	for i, label := range labels {
+		s := stats{Bot: label, Games: len(results)}
+		var length, ticks int64
+		for _, game := range results {
+			o := game[i]
+			if o.won {
+				s.Wins++
+			}
+			if o.ticks == *maxTicks {
+				s.Survived++
+			}
+			length += int64(o.length)
+			ticks += o.ticks
+		}
+		if s.Games > 0 {
+			n := float64(s.Games)
+			s.WinRate = float64(s.Wins) / n
+			s.AvgLength = float64(length) / n
+			s.AvgTicks = float64(ticks) / n
+		}
+		all[i] = s
+	}
+	return all
+}
+
+// play runs a single game between the named bots, by the same rules as the
+// server: they all start in the middle of the board, each has its own food, and
+// they only die by running into themselves or off the board. Whoever survives
+// longest wins, with ties going to the longest snek, and anything still tied
+// being a draw.
+func play(names []string, seed int64) []outcome {
+	rng := rand.New(rand.NewSource(seed))
+	board := rules.NewBoard(*wrap)
+	sneks := make([]*rules.Game, len(names))
+	bots := make([]bot.Player, len(names))
+	for i, name := range names {
+		sneks[i] = rules.NewGame(board, rand.New(rand.NewSource(rng.Int63())))
+		bots[i] = players[name](rand.New(rand.NewSource(rng.Int63())))
+	}
+
+	out := make([]outcome, len(names))
+	alive := len(names)
+	dead := make([]bool, len(names))
+	for tick := int64(1); tick <= *maxTicks && alive > 0; tick++ {
+		// Everyone decides where to go before anyone moves
+		dirs := make([]rules.Direction, len(names))
+		for i := range names {
+			if dead[i] {
+				continue
+			}
+			others := make(map[int32]*rules.Snek)
+			for j, g := range sneks {
+				if j != i && !dead[j] {
+					others[int32(j+1)] = g.Snek
+				}
+			}
+			dirs[i] = bots[i].Move(&bot.State{Board: board, Tick: tick, You: sneks[i].Snek, Others: others, Food: sneks[i].Food})
+		}
+
+		for i, g := range sneks {
+			if dead[i] {
+				continue
+			}
+			if _, ok := g.Step(dirs[i]); !ok {
+				dead[i] = true
+				alive--
+				continue
+			}
+			out[i].ticks = tick
+		}
+	}
+
+	best := -1
+	for i, g := range sneks {
+		out[i].length = len(g.Snek.Body)
+		if best < 0 || better(out[i], out[best]) {
+			best = i
+		}
+	}
+	for i := range out {
+		if i != best && !better(out[best], out[i]) {
+			// It's a draw
+			return out
+		}
+	}
+	out[best].won = true
+	return out
+}
+
+func better(a, b outcome) bool {
+	if a.ticks != b.ticks {
+		return a.ticks > b.ticks
+	}
+	return a.length > b.length
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"log"
 	"math/rand"
@@ -9,31 +10,14 @@ import (
 
 	"github.com/nsf/termbox-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
+	"github.com/bcspragu/Snek/levels"
 	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
 )
 
-type Loc struct {
-	X, Y int
-}
-
-type Direction struct {
-	X, Y int
-}
-
 var (
-	Up    = Direction{0, -1}
-	Down  = Direction{0, 1}
-	Left  = Direction{-1, 0}
-	Right = Direction{1, 0}
-
-	oppMap = map[Direction]Direction{
-		Up:    Down,
-		Down:  Up,
-		Left:  Right,
-		Right: Left,
-	}
-
 	playerColors = []termbox.Attribute{
 		termbox.ColorRed,
 		termbox.ColorGreen,
@@ -59,192 +43,347 @@ func (b bbox) CenterX() int { return b.x + b.w/2 }
 func (b bbox) CenterY() int { return b.y + b.h/2 }
 
 type Game struct {
-	snek       *Snek
+	// Our snek and its food, and how many times it's moved
+	play       *rules.Game
 	bbox       bbox
-	wrap       bool
 	suspend    bool
-	food       Loc
 	onlineFunc func(*pb.UpdateRequest) error
-	nextDirs   []Direction
+	inbox      chan *pb.UpdateResponse
+	notice     string
+	predict    *prediction
+	opponents  map[int32]*opponent
+	nextDirs   []rules.Direction
 	colors     map[int32]termbox.Attribute
+	// Our own snek's id on the server, and its colour
+	you   int32
+	color termbox.Attribute
+	// The teams in a team game, with their scores
+	teams []*pb.Team
+	// Set when we're playing against the clock
+	clock *countdown
+	// The way we went every tick of the daily challenge
+	moves []pb.PhoneType
+	// The campaign level we're playing, if we are, how much food we've eaten on
+	// it, and whether we've beaten it
+	level *levels.Level
+	eaten int
+	won   bool
 }
 
-type Move struct {
-	X, Y      int
-	Direction Direction
-}
-
-func newGame(wrap bool) *Game {
-	bbox := calcBbox()
+func newGame(wrap bool, lvl *levels.Level) *Game {
+	seed := time.Now().UnixNano()
+	var play *rules.Game
+	if lvl != nil {
+		play = lvl.NewGame(seed)
+	} else {
+		play = rules.NewGame(rules.NewBoard(wrap), rand.New(rand.NewSource(seed)))
+	}
 	g := &Game{
-		snek:     newSnek(bbox.CenterX(), bbox.CenterY(), 10),
-		bbox:     bbox,
-		wrap:     wrap,
-		nextDirs: []Direction{},
-		colors:   make(map[int32]termbox.Attribute),
+		play:      play,
+		bbox:      calcBbox(),
+		nextDirs:  []rules.Direction{},
+		colors:    make(map[int32]termbox.Attribute),
+		color:     termbox.ColorWhite,
+		inbox:     make(chan *pb.UpdateResponse, 256),
+		predict:   newPrediction(),
+		opponents: make(map[int32]*opponent),
+	}
+	if lvl != nil {
+		g.startLevel(lvl)
+	}
+	// Generated arenas are open enough for portals, but campaign levels are
+	// made to be played as they are
+	if *portals > 0 && (lvl == nil || *arena != "") {
+		g.play.AddPortals(*portals)
 	}
-	g.newFood()
 	g.drawBorder()
+	g.drawWalls()
+	g.drawPortals()
+	g.drawFood()
+	g.drawHazards()
 	return g
 }
 
-func (g *Game) addDirection(d Direction) {
+func (g *Game) addDirection(d rules.Direction) {
 	// Get the last direction
-	var ld Direction
+	var ld rules.Direction
 	if len(g.nextDirs) == 0 {
 		// If our queue is empty, the last direction is the current direction
-		ld = g.snek.dir
+		ld = g.play.Snek.Dir
 	} else {
 		// If our queue isn't empty, the last direction is at the end of the queue
 		ld = g.nextDirs[len(g.nextDirs)-1]
 	}
 
 	// If the next direction isn't the opposite of the direction the player wants to go, add it to the queue
-	if oppMap[d] != ld {
+	if d.Opposite() != ld {
 		g.nextDirs = append(g.nextDirs, d)
 	}
 }
 
-func (g *Game) goOnline(addr string) {
-	conn, err := grpc.Dial(addr, grpc.WithInsecure())
+func (g *Game) goOnline(ctx context.Context, addr string, creds grpc.DialOption) {
+	conn, err := grpc.Dial(addr, creds)
 	if err != nil {
 		log.Fatalf("failed to connect: %s", err)
 	}
 	defer conn.Close()
+	// Closing the inbox tells the game loop we're on our own, however we got
+	// cut off, so it starts keeping its own time
+	defer close(g.inbox)
 
 	client := pb.NewSnekClient(conn)
-	stream, err := client.Update(context.Background())
+	stream, err := client.Update(ctx)
+	if err != nil {
+		g.inbox <- &pb.UpdateResponse{Notice: "Failed to start stream: " + status.Convert(err).Message()}
+		return
+	}
+	defer stream.CloseSend()
 
 	g.onlineFunc = func(req *pb.UpdateRequest) error {
 		return stream.Send(req)
 	}
 
+	// We hand everything we receive off to the game loop, which applies it in
+	// step with our own moves
 	for {
 		resp, err := stream.Recv()
 		if err == io.EOF {
 			return
 		}
 		if err != nil {
-			log.Printf("Error reading from server: %v", err)
-		}
-		if _, ok := g.colors[resp.Id]; !ok {
-			g.colors[resp.Id] = playerColors[len(g.colors)%len(playerColors)]
+			// Let the player know why we're on our own now
+			g.inbox <- &pb.UpdateResponse{Notice: status.Convert(err).Message()}
+			return
 		}
-		g.drawSnek(resp)
+		g.inbox <- resp
 	}
-	stream.CloseSend()
 }
 
-func (g *Game) drawSnek(resp *pb.UpdateResponse) {
-	// Draw the new head
-	hx, hy := int(resp.NewHead.X), int(resp.NewHead.Y)
-	c := g.colors[resp.Id]
-	termbox.SetCell(hx*2, hy, '█', c, termbox.ColorDefault)
-	termbox.SetCell(hx*2+1, hy, '█', c, termbox.ColorDefault)
+// receive handles an update from the server. Ticks from the server's clock
+// move our snek, our own moves are checked against what we predicted, and
+// everyone else's are queued up to be drawn on our next tick. It returns false
+// if our snek died.
+func (g *Game) receive(resp *pb.UpdateResponse) bool {
+	if resp.Id == 0 {
+		if resp.Config != nil {
+			// The room's rules win over our own flags
+			g.play.Wrap = resp.Config.Wrap
+		}
+		if resp.You != 0 {
+			g.you = resp.You
+		}
+		if resp.Spawn != nil {
+			g.respawn(locFromPB(resp.Spawn))
+		}
+		if len(resp.Teams) > 0 {
+			g.setTeams(resp.Teams)
+		}
+		if resp.Notice != "" {
+			g.notice = resp.Notice
+			g.drawHUD()
+			termbox.Flush()
+		}
+		if resp.Tick <= g.play.Tick {
+			return true
+		}
+		// update moves us forward a tick, so line ourselves up with the server
+		g.play.Tick = resp.Tick - 1
+		if int(resp.Margin) != g.play.Margin && !g.closeIn(int(resp.Margin)) {
+			return false
+		}
+		return g.update()
+	}
 
-	// Clear the old tail
-	tx, ty := int(resp.OldTail.X), int(resp.OldTail.Y)
-	termbox.SetCell(tx*2, ty, ' ', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(tx*2+1, ty, ' ', termbox.ColorDefault, termbox.ColorDefault)
-}
+	if resp.Dead && resp.Id == g.you {
+		// The server says we ran into something
+		return false
+	}
 
-func (g *Game) clearSnek() {
-	time.Sleep(time.Second)
-	for _, l := range g.snek.body {
-		termbox.SetCell(l.X*2, l.Y, ' ', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.SetCell(l.X*2+1, l.Y, ' ', termbox.ColorDefault, termbox.ColorDefault)
-		termbox.Flush()
-		time.Sleep(50 * time.Millisecond)
+	if resp.Ack {
+		m, ok := g.predict.ack(resp)
+		if !ok && m != nil {
+			g.reconcile(m, resp)
+		}
+		if !ok && !g.suspend {
+			// The server disagrees with what we drew, redraw from what we know
+			g.fullRefresh()
+			termbox.Flush()
+		}
+		return true
 	}
-}
 
-func (g *Game) newFood() {
-	g.food = Loc{X: g.bbox.Left()/2 + rand.Intn(g.bbox.Width()/2-1) + 1, Y: g.bbox.Top() + rand.Intn(g.bbox.Height()-1) + 1}
-	termbox.SetCell(g.food.X*2+1, g.food.Y, '◎', termbox.ColorWhite, termbox.ColorDefault)
-}
+	if resp.Dead {
+		// Clear them off the board right away, we don't need to see the rest of
+		// their moves
+		if o, ok := g.opponents[resp.Id]; ok {
+			for l := range o.cells {
+				g.clearCell(l)
+			}
+			delete(g.opponents, resp.Id)
+			termbox.Flush()
+		}
+		return true
+	}
 
-type Snek struct {
-	body        []Loc // head is at body[len(body)-1]
-	dir         Direction
-	moveHistory []Move
-	occupied    map[Loc]struct{}
+	o, ok := g.opponents[resp.Id]
+	if !ok {
+		o = newOpponent()
+		g.opponents[resp.Id] = o
+		if _, ok := g.colors[resp.Id]; !ok {
+			g.colors[resp.Id] = playerColors[len(g.colors)%len(playerColors)]
+		}
+	}
+	o.push(resp)
+	return true
 }
 
-func newSnek(x, y, l int) *Snek {
-	b := make([]Loc, l)
-	o := make(map[Loc]struct{})
-	for i := 0; i < l; i++ {
-		b[i].X = x / 2
-		b[i].Y = y
-		o[b[i]] = struct{}{}
+// reconcile puts our snek back where the server says a move we predicted
+// wrong left it, and plays the moves we've made since, that the server hasn't
+// acknowledged yet, again from there.
+func (g *Game) reconcile(m *predictedMove, resp *pb.UpdateResponse) {
+	// Start from our snek before the move, cut back to the tail the server says
+	// it moved off of
+	body := m.before
+	for i, l := range body {
+		if l == locFromPB(resp.OldTail) {
+			body = body[i:]
+			break
+		}
+	}
+	body = append(append([]rules.Loc{}, body...), locFromPB(resp.NewHead))
+	if !m.grew && len(body) > 1 {
+		body = body[1:]
+	}
+
+	for _, t := range g.predict.ticks() {
+		p := g.predict.pending[t]
+		p.before = append([]rules.Loc{}, body...)
+		head, _ := g.play.Next(body[len(body)-1], p.dir)
+		body = append(body, head)
+		p.head, p.tail = head, body[0]
+		if !p.grew {
+			body = body[1:]
+		}
+		g.predict.pending[t] = p
 	}
-	return &Snek{
-		body:     b,
-		dir:      Right,
-		occupied: o,
+	g.play.Snek.Body = body
+}
+
+// drawOpponents draws the next buffered move for each of the other sneks.
+func (g *Game) drawOpponents() {
+	for _, o := range g.opponents {
+		for _, resp := range o.next() {
+			g.drawSnek(resp)
+		}
 	}
 }
 
-// Returns whether or not we were successful
-func (s *Snek) addHead(l Loc) bool {
-	if _, ok := s.occupied[l]; ok {
-		// It's already occupied, fail it
-		return false
+// drawHUD draws the connection latency, any team scores, the time attack clock,
+// the level's goal and how long we've survived into the top border, and any
+// notice into the bottom one.
+func (g *Game) drawHUD() {
+	if g.notice != "" {
+		str := " " + g.notice + " "
+		drawString(g.bbox.CenterX(), g.bbox.Bottom(), str)
+	}
+	g.drawScores()
+	g.drawClock()
+	g.drawGoal()
+	g.drawSurvival()
+	if g.onlineFunc == nil {
+		return
+	}
+	str := " ping: -- "
+	if g.predict.rtt > 0 {
+		str = fmt.Sprintf(" ping: %dms ", g.predict.rtt/time.Millisecond)
+	}
+	x := g.bbox.Right() - len(str) - 1
+	for i, r := range str {
+		termbox.SetCell(x+i, g.bbox.Top(), r, termbox.ColorWhite, termbox.ColorDefault)
 	}
-	s.occupied[l] = struct{}{}
-	s.body = append(s.body, l)
-	return true
 }
 
-func (s *Snek) removeTail() {
-	delete(s.occupied, s.body[0])
-	s.body = s.body[1:]
+// screenPos returns where the left half of a board cell is on the screen.
+// Cells are two characters wide, so they come out roughly square.
+func (g *Game) screenPos(l rules.Loc) (int, int) {
+	return (g.bbox.Left()/2 + 1 + l.X) * 2, g.bbox.Top() + 1 + l.Y
 }
 
-func (s *Snek) tail() Loc {
-	return s.body[0]
+func (g *Game) setCell(l rules.Loc, ch rune, fg termbox.Attribute) {
+	x, y := g.screenPos(l)
+	termbox.SetCell(x, y, ch, fg, termbox.ColorDefault)
+	termbox.SetCell(x+1, y, ch, fg, termbox.ColorDefault)
 }
 
-func (s *Snek) grow() {
-	s.body = append(s.body[0:1], s.body...)
+func (g *Game) clearCell(l rules.Loc) {
+	g.setCell(l, ' ', termbox.ColorDefault)
 }
 
-func (s *Snek) head() Loc {
-	return s.body[len(s.body)-1]
+func (g *Game) drawFood() {
+	x, y := g.screenPos(g.play.Food)
+	termbox.SetCell(x+1, y, '◎', termbox.ColorWhite, termbox.ColorDefault)
 }
 
-func (g *Game) addHead() (Loc, bool) {
-	h := g.snek.head()
-	nh := Loc{h.X + g.snek.dir.X, h.Y + g.snek.dir.Y}
+func (g *Game) drawSnek(resp *pb.UpdateResponse) {
+	// Draw the new head
+	g.setCell(locFromPB(resp.NewHead), '█', g.colors[resp.Id])
+	// Clear the old tail
+	g.clearCell(locFromPB(resp.OldTail))
+}
 
-	if g.wrap {
-		if nh.X <= g.bbox.Left()/2 {
-			nh.X = g.bbox.Right()/2 - 1
+// closeIn moves the edges of the arena in, and returns false if we were caught
+// outside.
+func (g *Game) closeIn(margin int) bool {
+	food := g.play.Food
+	if !g.play.CloseIn(margin) {
+		if g.onlineFunc != nil {
+			g.onlineFunc(&pb.UpdateRequest{Tick: g.play.Tick, Dead: true})
 		}
-		if nh.X >= g.bbox.Right()/2 {
-			nh.X = g.bbox.Left()/2 + 1
-		}
-		if nh.Y <= g.bbox.Top() {
-			nh.Y = g.bbox.Bottom() - 1
-		}
-		if nh.Y >= g.bbox.Bottom() {
-			nh.Y = g.bbox.Top() + 1
-		}
-	} else {
-		// We aren't wrapping, kill them if they go too far
-		if nh.X <= g.bbox.Left()/2 || nh.Y <= g.bbox.Top() || nh.X >= g.bbox.Right()/2 || nh.Y >= g.bbox.Bottom() {
-			return nh, false
+		return false
+	}
+	if g.suspend {
+		return true
+	}
+	g.drawArena()
+	if g.play.Food != food {
+		g.drawFood()
+	}
+	termbox.Flush()
+	return true
+}
+
+// drawArena shades in the parts of the board that are out of play.
+func (g *Game) drawArena() {
+	if g.play.Margin == 0 {
+		return
+	}
+	for x := 0; x < g.play.Width; x++ {
+		for y := 0; y < g.play.Height; y++ {
+			if l := (rules.Loc{X: x, Y: y}); !g.play.InBounds(l) {
+				g.setCell(l, '░', termbox.ColorRed)
+			}
 		}
 	}
-	return nh, g.snek.addHead(nh)
 }
 
-func (g *Game) updateDir() {
-	if len(g.nextDirs) > 0 {
-		g.snek.dir, g.nextDirs = g.nextDirs[0], g.nextDirs[1:]
-		h := g.snek.head()
-		g.snek.moveHistory = append(g.snek.moveHistory, Move{X: h.X, Y: h.Y, Direction: g.snek.dir})
+func (g *Game) clearSnek() {
+	time.Sleep(time.Second)
+	for _, l := range g.play.Snek.Body {
+		g.clearCell(l)
+		termbox.Flush()
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// nextDir returns the next direction the player asked for, or the way we're
+// already going.
+func (g *Game) nextDir() rules.Direction {
+	if len(g.nextDirs) == 0 {
+		return g.play.Snek.Dir
 	}
+	d := g.nextDirs[0]
+	g.nextDirs = g.nextDirs[1:]
+	return d
 }
 
 func calcBbox() bbox {
@@ -282,34 +421,51 @@ func (g *Game) update() bool {
 		return true
 	}
 
-	g.updateDir()
-
-	h, ok := g.addHead()
+	was := g.hazardCells()
+	var before []rules.Loc
+	if g.onlineFunc != nil {
+		before = append(before, g.play.Snek.Body...)
+	}
+	m, ok := g.play.Step(g.nextDir())
+	g.recordMove()
 	if !ok {
+		if g.onlineFunc != nil {
+			g.onlineFunc(&pb.UpdateRequest{Tick: g.play.Tick, Dead: true})
+		}
 		return false
 	}
-	// draw the new head
-	termbox.SetCell(h.X*2, h.Y, '█', termbox.ColorWhite, termbox.ColorDefault)
-	termbox.SetCell(h.X*2+1, h.Y, '█', termbox.ColorWhite, termbox.ColorDefault)
-
-	if h == g.food {
-		g.snek.grow()
-		// Clear the cell
-		termbox.SetCell(g.food.X*2+1, g.food.Y, '█', termbox.ColorWhite, termbox.ColorDefault)
-		g.newFood()
+	// draw the new head, which also covers up any food we ate
+	g.setCell(m.Head, '█', g.color)
+	if m.Ate {
+		g.eaten++
+		g.drawFood()
 	}
-
-	t := g.snek.tail()
-	g.snek.removeTail()
-	// clear the tail
-	termbox.SetCell(t.X*2, t.Y, ' ', termbox.ColorDefault, termbox.ColorDefault)
-	termbox.SetCell(t.X*2+1, t.Y, ' ', termbox.ColorDefault, termbox.ColorDefault)
+	// clear the tail, unless we're still covering it
+	if !g.play.Snek.Covers(m.Tail) {
+		g.clearTail(m.Tail)
+	}
+	if len(was) > 0 {
+		g.redrawHazards(was)
+	}
+	g.spawnHazards()
 
 	if g.onlineFunc != nil {
+		g.predict.add(g.play.Tick, before, g.play.Snek.Dir, m)
 		g.onlineFunc(&pb.UpdateRequest{
-			NewHead: &pb.Loc{X: int32(h.X), Y: int32(h.Y)},
-			OldTail: &pb.Loc{X: int32(t.X), Y: int32(t.Y)},
+			NewHead: locToPB(m.Head),
+			OldTail: locToPB(m.Tail),
+			Tick:    g.play.Tick,
 		})
+		g.drawOpponents()
+		g.drawHUD()
+	}
+	if g.clock != nil && !g.tickClock() {
+		termbox.Flush()
+		return false
+	}
+	if g.level != nil && g.checkGoal() {
+		termbox.Flush()
+		return false
 	}
 
 	termbox.Flush()
@@ -328,11 +484,22 @@ func (g *Game) unpause() {
 func (g *Game) fullRefresh() {
 	g.bbox = calcBbox()
 	g.drawBorder()
+	g.drawArena()
+	g.drawWalls()
+	g.drawPortals()
 
-	for _, p := range g.snek.body {
-		termbox.SetCell(p.X*2, p.Y, '█', termbox.ColorWhite, termbox.ColorDefault)
-		termbox.SetCell(p.X*2+1, p.Y, '█', termbox.ColorWhite, termbox.ColorDefault)
+	for _, p := range g.play.Snek.Body {
+		g.setCell(p, '█', g.color)
+	}
+
+	for id, o := range g.opponents {
+		for p := range o.cells {
+			g.setCell(p, '█', g.colors[id])
+		}
 	}
+	g.drawHUD()
 
-	termbox.SetCell(g.food.X*2+1, g.food.Y, '◎', termbox.ColorWhite, termbox.ColorDefault)
+	g.drawFood()
+	g.drawBonus()
+	g.drawHazards()
 }
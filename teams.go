@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+
+	pb "github.com/bcspragu/Snek/proto"
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+)
+
+// Everyone on a team shares its colour, with our own snek in the bright version
+// of it.
+var teamColors = []termbox.Attribute{
+	termbox.ColorRed,
+	termbox.ColorBlue,
+	termbox.ColorGreen,
+	termbox.ColorYellow,
+	termbox.ColorMagenta,
+	termbox.ColorCyan,
+}
+
+func teamColor(team int32) termbox.Attribute {
+	return teamColors[int(team-1)%len(teamColors)]
+}
+
+// respawn moves our snek to where the server wants it to start.
+func (g *Game) respawn(at rules.Loc) {
+	for _, l := range g.play.Snek.Body {
+		g.clearCell(l)
+	}
+	food := g.play.Food
+	g.play.Respawn(at)
+	if g.play.Food != food {
+		g.clearCell(food)
+		g.drawFood()
+	}
+}
+
+// setTeams colours everyone by their team, redrawing the board if anyone's
+// colour changed.
+func (g *Game) setTeams(teams []*pb.Team) {
+	g.teams = teams
+	changed := false
+	for _, t := range teams {
+		c := teamColor(t.Number)
+		for _, id := range t.Members {
+			if id == g.you {
+				if g.color != c|termbox.AttrBold {
+					g.color = c | termbox.AttrBold
+					changed = true
+				}
+				continue
+			}
+			if g.colors[id] != c {
+				g.colors[id] = c
+				changed = true
+			}
+		}
+	}
+	if g.suspend {
+		return
+	}
+	if changed {
+		g.fullRefresh()
+	} else {
+		g.drawHUD()
+	}
+	termbox.Flush()
+}
+
+// drawScores draws each team's score into the top border, in its colour.
+func (g *Game) drawScores() {
+	x := g.bbox.Left() + 2
+	for _, t := range g.teams {
+		str := fmt.Sprintf(" Team %d: %d ", t.Number, t.Score)
+		for i, r := range str {
+			termbox.SetCell(x+i, g.bbox.Top(), r, teamColor(t.Number)|termbox.AttrBold, termbox.ColorDefault)
+		}
+		x += len(str)
+	}
+}
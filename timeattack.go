@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/bcspragu/Snek/rules"
+	termbox "github.com/nsf/termbox-go"
+)
+
+const (
+	// How much time eating bonus food gives back
+	bonusTime = 5 * time.Second
+	// How long bonus food sticks around before it disappears
+	bonusLife = 5 * time.Second
+	// On any given tick, there's a 1 in bonusChance chance of bonus food showing
+	// up, if there isn't already some on the board
+	bonusChance = 100
+	// How many scores we keep for each length of game
+	maxHighScores = 10
+)
+
+// countdown is the clock in time attack, where the player has a fixed amount of
+// time to grow as long as they can. It counts ticks, so it stops when the game
+// is paused.
+type countdown struct {
+	// How many ticks are left
+	left int64
+	// Where the bonus food is, and how many ticks until it disappears, 0 if
+	// there isn't any
+	bonus     rules.Loc
+	bonusLeft int64
+	rng       *rand.Rand
+}
+
+func newCountdown(d time.Duration) *countdown {
+	return &countdown{
+		left: int64(d / localTick),
+		rng:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// tickClock counts down a tick, and places, expires or eats bonus food. It
+// returns false once time's up.
+func (g *Game) tickClock() bool {
+	c := g.clock
+	c.left--
+	switch {
+	case c.bonusLeft > 0 && g.play.Snek.Head() == c.bonus:
+		c.left += int64(bonusTime / localTick)
+		c.bonusLeft = 0
+		g.notice = fmt.Sprintf("+%ds", bonusTime/time.Second)
+	case c.bonusLeft > 0:
+		c.bonusLeft--
+		if c.bonusLeft == 0 && !g.play.Snek.Covers(c.bonus) {
+			g.clearCell(c.bonus)
+		}
+	case *bonusFood && c.rng.Intn(bonusChance) == 0:
+		free := g.play.FreeCells()
+		if len(free) == 0 {
+			break
+		}
+		c.bonus = free[c.rng.Intn(len(free))]
+		c.bonusLeft = int64(bonusLife / localTick)
+		g.drawBonus()
+	}
+	g.drawHUD()
+	return c.left > 0
+}
+
+func (g *Game) drawBonus() {
+	if g.clock == nil || g.clock.bonusLeft == 0 {
+		return
+	}
+	x, y := g.screenPos(g.clock.bonus)
+	termbox.SetCell(x+1, y, '✚', termbox.ColorYellow, termbox.ColorDefault)
+}
+
+// drawClock draws how much time is left and how long we are into the top
+// border.
+func (g *Game) drawClock() {
+	if g.clock == nil {
+		return
+	}
+	left := time.Duration(g.clock.left) * localTick
+	str := fmt.Sprintf(" Time %d:%02d  Length %d ", int(left.Minutes()), int(left.Seconds())%60, len(g.play.Snek.Body))
+	fg := termbox.ColorWhite
+	if left < 10*time.Second {
+		fg = termbox.ColorRed
+	}
+	for i, r := range str {
+		termbox.SetCell(g.bbox.Left()+2+i, g.bbox.Top(), r, fg, termbox.ColorDefault)
+	}
+}
+
+// highScore is one finished time attack game.
+type highScore struct {
+	Name   string    `json:"name"`
+	Length int       `json:"length"`
+	Time   time.Time `json:"time"`
+}
+
+// highScores are the best time attack games, kept separately for each length
+// of game since they aren't comparable.
+type highScores map[string][]highScore
+
+// add records a score for a length of game, and returns where it placed in the
+// table, or -1 if it didn't make it.
+func (h highScores) add(d time.Duration, s highScore) int {
+	key := d.String()
+	table := append(h[key], s)
+	sort.SliceStable(table, func(i, j int) bool { return table[i].Length > table[j].Length })
+	if len(table) > maxHighScores {
+		table = table[:maxHighScores]
+	}
+	h[key] = table
+	for i := range table {
+		if table[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// finishTimeAttack records how long our snek got, and shows the high scores
+// for this length of game until a key is pressed.
+func finishTimeAttack(evChan chan *termbox.Event, d time.Duration, timeUp bool) error {
+	fn := homeFile(*scoresPath, ".snek_scores.json")
+	scores := make(highScores)
+	if err := loadJSON(fn, &scores); err != nil {
+		return err
+	}
+	player := *name
+	if player == "" {
+		player = os.Getenv("USER")
+	}
+	length := len(game.play.Snek.Body)
+	place := scores.add(d, highScore{Name: player, Length: length, Time: time.Now().Round(time.Second)})
+	if err := saveJSON(fn, scores); err != nil {
+		return err
+	}
+
+	title := "You died!"
+	if timeUp {
+		title = "Time's up!"
+	}
+	lines := []string{
+		title,
+		fmt.Sprintf("You got to a length of %d in %s", length, d),
+		"",
+		fmt.Sprintf("%4s  %-20s  %6s  %-10s", "#", "Name", "Length", "Date"),
+	}
+	for i, s := range scores[d.String()] {
+		mark := " "
+		if i == place {
+			mark = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s%3d  %-20.20s  %6d  %-10s", mark, i+1, s.Name, s.Length, s.Time.Format("2006-01-02")))
+	}
+	lines = append(lines, "", "Press any key to quit")
+	drawLines(lines)
+
+	for ev := range evChan {
+		if ev.Type == termbox.EventKey {
+			return nil
+		}
+	}
+	return nil
+}